@@ -46,6 +46,11 @@ func (c Chunk) FileID() string {
 	return ""
 }
 
+// IsManifest reports whether the Chunk is a manifest, where the content of the Chunk is a list of other chunks.
+func (c Chunk) IsManifest() bool {
+	return c.PB().GetIsChunkManifest()
+}
+
 // Offset returns the Offset for the Chunk.
 func (c Chunk) Offset() Offset {
 	return c.offset
@@ -42,6 +42,8 @@ func NewChunks(path string, options ...func(*Chunks)) (*Chunks, error) {
 }
 
 // Add adds one or more protobuf chunks to Chunks.
+//
+// If a chunk with the same offset is already present, it is replaced if the provided chunk was modified more recently.
 func (c *Chunks) Add(chunks ...*filer_pb.FileChunk) (int, error) {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
@@ -63,15 +65,17 @@ func (c *Chunks) Add(chunks ...*filer_pb.FileChunk) (int, error) {
 	var n int
 	for _, fc := range chunks {
 		off := Offset{Start: fc.GetOffset(), End: fc.GetOffset() + int64(fc.GetSize())}
-		if _, ok := c.chunks[off]; !ok {
+		if ck, ok := c.chunks[off]; !ok || ck.PB().GetModifiedTsNs() < fc.GetModifiedTsNs() {
 			ck, err := NewChunk(fc, WithPosition(uint(p)))
 			if err != nil {
 				return n, err
 			}
 
 			c.chunks[off] = ck
-			c.setChunkMinMax(ck.Size())
-			c.size += ck.Size()
+			if !ck.IsManifest() {
+				c.setChunkMinMax(ck.Size())
+			}
+			c.size = max(c.size, off.End)
 			n++
 			p++
 		}
@@ -176,11 +180,62 @@ func (c *Chunks) PB() ([]*filer_pb.FileChunk, error) {
 	return values, nil
 }
 
-// Size returns the total size in bytes for all the chunks.
+// Remove removes one or more protobuf chunks from Chunks.
+func (c *Chunks) Remove(chunks ...*filer_pb.FileChunk) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for _, fc := range chunks {
+		off := Offset{Start: fc.GetOffset(), End: fc.GetOffset() + int64(fc.GetSize())}
+		if ck, ok := c.chunks[off]; ok && ck.FileID() == fc.GetFileId() {
+			delete(c.chunks, off)
+		}
+	}
+}
+
+// Size returns the size in bytes of the content represented by the chunks, which is the end of the chunk with the
+// greatest offset.
 func (c *Chunks) Size() int64 {
 	return c.size
 }
 
+// Truncate changes the size of the content represented by Chunks and returns the protobuf chunks that were removed.
+//
+// Chunks starting at or beyond size are removed, and a chunk containing size is trimmed so that it ends at size. The
+// content of a trimmed chunk is left intact. If size is greater than the current size, the content is extended with
+// a hole.
+func (c *Chunks) Truncate(size int64) ([]*filer_pb.FileChunk, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if size < 0 {
+		return nil, errors.New(fmt.Sprintf("chunks: invalid size %d", size))
+	}
+
+	var removed []*filer_pb.FileChunk
+	for off, ck := range c.chunks {
+		if off.End <= size {
+			continue
+		}
+		delete(c.chunks, off)
+
+		if off.Start >= size {
+			removed = append(removed, ck.PB())
+			continue
+		}
+
+		fc := ck.PB()
+		fc.Size = uint64(size - off.Start)
+		trimmed, err := NewChunk(fc, WithPosition(uint(ck.Position())))
+		if err != nil {
+			return removed, err
+		}
+		c.chunks[trimmed.Offset()] = trimmed
+	}
+	c.size = size
+	return removed, nil
+}
+
 // Values returns the chunks a slice.
 func (c *Chunks) Values() []Chunk {
 	cks, err := c.List()
@@ -190,6 +245,18 @@ func (c *Chunks) Values() []Chunk {
 	return cks.Values()
 }
 
+// Views returns the list of Views representing the visible content for the chunks ordered by offset.
+func (c *Chunks) Views() []View {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	cks := make([]Chunk, 0, len(c.chunks))
+	for _, ck := range c.chunks {
+		cks = append(cks, ck)
+	}
+	return views(cks, c.size)
+}
+
 // ToMap returns a map representing the properties of Chunks.
 func (c *Chunks) ToMap() map[string]any {
 	m := make(map[string]any)
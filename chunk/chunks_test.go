@@ -0,0 +1,106 @@
+package chunk
+
+import (
+	"testing"
+
+	"github.com/transientvariable/lettuce/pb/filer_pb"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChunksTruncate(t *testing.T) {
+	tests := []struct {
+		name    string
+		size    int64
+		removed []string
+		want    []testView
+	}{
+		{
+			name: "same size",
+			size: 12,
+			want: []testView{{fileID: "a", start: 0, end: 4}, {fileID: "b", start: 4, end: 8}, {fileID: "c", start: 8, end: 12}},
+		},
+		{
+			name:    "chunk boundary",
+			size:    8,
+			removed: []string{"c"},
+			want:    []testView{{fileID: "a", start: 0, end: 4}, {fileID: "b", start: 4, end: 8}},
+		},
+		{
+			name:    "within chunk",
+			size:    6,
+			removed: []string{"c"},
+			want:    []testView{{fileID: "a", start: 0, end: 4}, {fileID: "b", start: 4, end: 6}},
+		},
+		{
+			name:    "zero",
+			size:    0,
+			removed: []string{"a", "b", "c"},
+		},
+		{
+			name: "extend",
+			size: 20,
+			want: []testView{{fileID: "a", start: 0, end: 4}, {fileID: "b", start: 4, end: 8}, {fileID: "c", start: 8, end: 12}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewChunks("/test")
+			require.NoError(t, err)
+
+			_, err = c.Add(testChunk("a", 0, 4, 1), testChunk("b", 4, 4, 1), testChunk("c", 8, 4, 1))
+			require.NoError(t, err)
+
+			removed, err := c.Truncate(tt.size)
+			require.NoError(t, err)
+			assert.Equal(t, tt.size, c.Size())
+
+			var got []string
+			for _, fc := range removed {
+				got = append(got, fc.GetFileId())
+			}
+			assert.ElementsMatch(t, tt.removed, got)
+
+			pbs, err := c.PB()
+			require.NoError(t, err)
+
+			var remaining []testView
+			for _, fc := range pbs {
+				remaining = append(remaining, testView{
+					fileID: fc.GetFileId(),
+					start:  fc.GetOffset(),
+					end:    fc.GetOffset() + int64(fc.GetSize()),
+				})
+			}
+			assert.Equal(t, tt.want, remaining)
+		})
+	}
+}
+
+func TestChunksTruncateViews(t *testing.T) {
+	c, err := NewChunks("/test")
+	require.NoError(t, err)
+
+	_, err = c.Add(testChunk("a", 0, 4, 1))
+	require.NoError(t, err)
+
+	_, err = c.Truncate(8)
+	require.NoError(t, err)
+
+	vs := c.Views()
+	require.Len(t, vs, 2)
+	assert.Equal(t, "a", vs[0].Chunk.FileID())
+	assert.True(t, vs[1].IsHole())
+	assert.Equal(t, Offset{Start: 4, End: 8}, vs[1].Offset)
+}
+
+func TestChunksTruncateInvalid(t *testing.T) {
+	c, err := NewChunks("/test", WithEntry(&filer_pb.Entry{Chunks: []*filer_pb.FileChunk{testChunk("a", 0, 4, 1)}}))
+	require.NoError(t, err)
+
+	_, err = c.Truncate(-1)
+	assert.Error(t, err)
+	assert.Equal(t, int64(4), c.Size())
+}
@@ -0,0 +1,56 @@
+package chunk
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+)
+
+const (
+	// cipherKeySize defines the size in bytes of the AES-256 keys used for encrypting chunk content.
+	cipherKeySize = 32
+)
+
+// encrypt encrypts b with AES-GCM using a new random key, and returns the ciphertext and key.
+//
+// The nonce is prepended to the ciphertext, which is the same format used by SeaweedFS, so chunks encrypted by a Writer
+// can be read by other SeaweedFS clients.
+func encrypt(b []byte) ([]byte, []byte, error) {
+	key := make([]byte, cipherKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, nil, err
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, nil, err
+	}
+	return gcm.Seal(nonce, nonce, b, nil), key, nil
+}
+
+// decrypt decrypts b, which must be in the format returned by encrypt, using the provided key.
+func decrypt(b []byte, key []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(b) < gcm.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	return gcm.Open(nil, b[:gcm.NonceSize()], b[gcm.NonceSize():], nil)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	c, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(c)
+}
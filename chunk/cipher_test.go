@@ -0,0 +1,73 @@
+package chunk
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncrypt(t *testing.T) {
+	tests := []struct {
+		name string
+		b    []byte
+	}{
+		{name: "empty", b: []byte{}},
+		{name: "text", b: []byte("lettuce")},
+		{name: "chunk", b: bytes.Repeat([]byte{0xa5}, Size)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, key, err := encrypt(tt.b)
+			require.NoError(t, err)
+			assert.Len(t, key, cipherKeySize)
+			assert.NotEqual(t, tt.b, c)
+
+			d, err := decrypt(c, key)
+			require.NoError(t, err)
+			assert.True(t, bytes.Equal(tt.b, d))
+		})
+	}
+}
+
+func TestEncryptUniqueKey(t *testing.T) {
+	c1, k1, err := encrypt([]byte("lettuce"))
+	require.NoError(t, err)
+
+	c2, k2, err := encrypt([]byte("lettuce"))
+	require.NoError(t, err)
+
+	assert.NotEqual(t, k1, k2)
+	assert.NotEqual(t, c1, c2)
+}
+
+func TestDecryptInvalid(t *testing.T) {
+	c, key, err := encrypt([]byte("lettuce"))
+	require.NoError(t, err)
+
+	tampered := bytes.Clone(c)
+	tampered[len(tampered)-1] ^= 0xff
+
+	wrongKey := bytes.Clone(key)
+	wrongKey[0] ^= 0xff
+
+	tests := []struct {
+		name string
+		b    []byte
+		key  []byte
+	}{
+		{name: "too short", b: c[:4], key: key},
+		{name: "tampered", b: tampered, key: key},
+		{name: "wrong key", b: c, key: wrongKey},
+		{name: "invalid key size", b: c, key: key[:7]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := decrypt(tt.b, tt.key)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package chunk
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompress(t *testing.T) {
+	tests := []struct {
+		name        string
+		compression Compression
+		b           []byte
+	}{
+		{name: "gzip", compression: CompressionGzip, b: bytes.Repeat([]byte("lettuce "), 1024)},
+		{name: "gzip empty", compression: CompressionGzip, b: []byte{}},
+		{name: "zstd", compression: CompressionZstd, b: bytes.Repeat([]byte("lettuce "), 1024)},
+		{name: "zstd empty", compression: CompressionZstd, b: []byte{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := compress(tt.compression, tt.b)
+			require.NoError(t, err)
+
+			d, err := decompress(c)
+			require.NoError(t, err)
+			assert.True(t, bytes.Equal(tt.b, d))
+		})
+	}
+}
+
+func TestCompressUnsupported(t *testing.T) {
+	_, err := compress(CompressionNone, []byte("lettuce"))
+	assert.Error(t, err)
+
+	_, err = compress("lz4", []byte("lettuce"))
+	assert.Error(t, err)
+}
+
+func TestDecompressUncompressed(t *testing.T) {
+	tests := []struct {
+		name string
+		b    []byte
+	}{
+		{name: "empty", b: []byte{}},
+		{name: "text", b: []byte("lettuce")},
+		{name: "partial magic", b: []byte{0x1f}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := decompress(tt.b)
+			require.NoError(t, err)
+			assert.Equal(t, tt.b, d)
+		})
+	}
+}
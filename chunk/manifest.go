@@ -0,0 +1,122 @@
+package chunk
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/transientvariable/lettuce/pb/filer_pb"
+
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// ManifestBatch defines the default number of chunks that are folded into a single manifest chunk. This is the same
+	// value used by SeaweedFS.
+	ManifestBatch = 10000
+)
+
+// ResolveManifest defines the signature for the function used for retrieving the content of a manifest chunk.
+type ResolveManifest func(context.Context, Chunk) ([]byte, error)
+
+// resolveManifests returns the data chunks for the provided chunks, where the content of each manifest chunk is
+// retrieved using fn and resolved recursively.
+func resolveManifests(ctx context.Context, chunks []Chunk, fn ResolveManifest) ([]Chunk, error) {
+	var resolved []Chunk
+	for _, c := range chunks {
+		if !c.IsManifest() {
+			resolved = append(resolved, c)
+			continue
+		}
+
+		b, err := fn(ctx, c)
+		if err != nil {
+			return nil, fmt.Errorf("manifest %s: %w", c.FileID(), err)
+		}
+
+		m := &filer_pb.FileChunkManifest{}
+		if err := proto.Unmarshal(b, m); err != nil {
+			return nil, fmt.Errorf("manifest %s: %w", c.FileID(), err)
+		}
+
+		cks := make([]Chunk, len(m.GetChunks()))
+		for i, fc := range m.GetChunks() {
+			if fc.GetFileId() == "" && fc.GetFid() != nil {
+				fc.FileId = formatFID(fc.GetFid())
+			}
+
+			if cks[i], err = NewChunk(fc, WithPosition(uint(c.Position()))); err != nil {
+				return nil, err
+			}
+		}
+
+		cks, err = resolveManifests(ctx, cks, fn)
+		if err != nil {
+			return nil, err
+		}
+		resolved = append(resolved, cks...)
+	}
+	return resolved, nil
+}
+
+// manifest returns the serialized manifest for the provided data chunks, along with a filer_pb.FileChunk describing
+// the range of the content covered by the manifest. The file ID for the returned filer_pb.FileChunk is not set.
+//
+// As with SeaweedFS, chunks within a manifest only reference their content using filer_pb.FileChunk.Fid.
+func manifest(chunks []*filer_pb.FileChunk) ([]byte, *filer_pb.FileChunk, error) {
+	m := &filer_pb.FileChunkManifest{Chunks: make([]*filer_pb.FileChunk, len(chunks))}
+	start, end := chunks[0].GetOffset(), int64(0)
+	for i, fc := range chunks {
+		start = min(start, fc.GetOffset())
+		end = max(end, fc.GetOffset()+int64(fc.GetSize()))
+
+		c := proto.Clone(fc).(*filer_pb.FileChunk)
+		if c.GetFid() != nil {
+			c.FileId = ""
+		}
+		m.Chunks[i] = c
+	}
+
+	b, err := proto.Marshal(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	return b, &filer_pb.FileChunk{
+		IsChunkManifest: true,
+		ModifiedTsNs:    time.Now().UnixNano(),
+		Offset:          start,
+		Size:            uint64(end - start),
+	}, nil
+}
+
+// foldable returns the data chunks to fold into a manifest if the number of data chunks has reached batch, ordered by
+// offset. Otherwise, nil is returned.
+func foldable(chunks []*filer_pb.FileChunk, batch int) []*filer_pb.FileChunk {
+	var data []*filer_pb.FileChunk
+	for _, fc := range chunks {
+		if !fc.GetIsChunkManifest() {
+			data = append(data, fc)
+		}
+	}
+
+	if batch <= 0 || len(data) < batch {
+		return nil
+	}
+	sort.Slice(data, func(i int, j int) bool { return data[i].GetOffset() < data[j].GetOffset() })
+	return data[:batch]
+}
+
+// formatFID returns the string representation of the provided filer_pb.FileId (e.g. 3,01637037d6).
+func formatFID(fid *filer_pb.FileId) string {
+	b := make([]byte, NeedleIdSize+CookieSize)
+	binary.BigEndian.PutUint64(b, fid.GetFileKey())
+	binary.BigEndian.PutUint32(b[NeedleIdSize:], fid.GetCookie())
+
+	var i int
+	for i < NeedleIdSize && b[i] == 0 {
+		i++
+	}
+	return fmt.Sprintf("%d,%x", fid.GetVolumeId(), b[i:])
+}
@@ -0,0 +1,169 @@
+package chunk
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/transientvariable/lettuce/pb/filer_pb"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatFID(t *testing.T) {
+	tests := []struct {
+		fid  string
+		want string
+	}{
+		{fid: "3,01637037d6", want: "3,01637037d6"},
+		{fid: "7,1234567890deadbeef", want: "7,1234567890deadbeef"},
+		{fid: "12,ffffffffffffffff00000001", want: "12,ffffffffffffffff00000001"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fid, func(t *testing.T) {
+			fid, err := parseFID(tt.fid)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, formatFID(fid))
+		})
+	}
+}
+
+func TestManifest(t *testing.T) {
+	chunks := []*filer_pb.FileChunk{
+		testManifestChunk(t, "3,01637037d6", 4, 4),
+		testManifestChunk(t, "3,02637037d6", 0, 4),
+		testManifestChunk(t, "4,03637037d6", 8, 2),
+	}
+
+	b, mc, err := manifest(chunks)
+	require.NoError(t, err)
+	assert.True(t, mc.GetIsChunkManifest())
+	assert.Empty(t, mc.GetFileId())
+	assert.Equal(t, int64(0), mc.GetOffset())
+	assert.Equal(t, uint64(10), mc.GetSize())
+
+	for _, c := range chunks {
+		assert.NotEmpty(t, c.GetFileId(), "chunks provided to manifest must not be modified")
+	}
+
+	mc.FileId = "5,04637037d6"
+	c, err := NewChunk(mc)
+	require.NoError(t, err)
+
+	resolved, err := resolveManifests(context.Background(), []Chunk{c}, func(context.Context, Chunk) ([]byte, error) {
+		return b, nil
+	})
+	require.NoError(t, err)
+	require.Len(t, resolved, len(chunks))
+
+	for i, r := range resolved {
+		assert.Equal(t, chunks[i].GetFileId(), r.FileID())
+		assert.Equal(t, chunks[i].GetOffset(), r.Offset().Start)
+		assert.Equal(t, int64(chunks[i].GetSize()), r.Size())
+	}
+}
+
+func TestResolveManifestsNested(t *testing.T) {
+	inner, imc, err := manifest([]*filer_pb.FileChunk{testManifestChunk(t, "3,01637037d6", 0, 4)})
+	require.NoError(t, err)
+	imc.Fid, err = parseFID("5,04637037d6")
+	require.NoError(t, err)
+
+	outer, omc, err := manifest([]*filer_pb.FileChunk{imc, testManifestChunk(t, "3,02637037d6", 4, 4)})
+	require.NoError(t, err)
+	omc.FileId = "6,05637037d6"
+
+	c, err := NewChunk(omc)
+	require.NoError(t, err)
+
+	content := map[string][]byte{"5,04637037d6": inner, "6,05637037d6": outer}
+	resolved, err := resolveManifests(context.Background(), []Chunk{c}, func(_ context.Context, c Chunk) ([]byte, error) {
+		return content[c.FileID()], nil
+	})
+	require.NoError(t, err)
+	require.Len(t, resolved, 2)
+	assert.Equal(t, "3,01637037d6", resolved[0].FileID())
+	assert.Equal(t, "3,02637037d6", resolved[1].FileID())
+}
+
+func TestResolveManifestsError(t *testing.T) {
+	errResolve := errors.New("resolve failed")
+
+	tests := []struct {
+		name string
+		fn   ResolveManifest
+		want error
+	}{
+		{
+			name: "resolve error",
+			fn:   func(context.Context, Chunk) ([]byte, error) { return nil, errResolve },
+			want: errResolve,
+		},
+		{
+			name: "invalid manifest",
+			fn:   func(context.Context, Chunk) ([]byte, error) { return []byte{0xff}, nil },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewChunk(&filer_pb.FileChunk{FileId: "5,04637037d6", IsChunkManifest: true, Size: 10})
+			require.NoError(t, err)
+
+			_, err = resolveManifests(context.Background(), []Chunk{c}, tt.fn)
+			require.Error(t, err)
+			assert.ErrorContains(t, err, "manifest 5,04637037d6")
+			if tt.want != nil {
+				assert.ErrorIs(t, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestFoldable(t *testing.T) {
+	data := []*filer_pb.FileChunk{
+		{FileId: "a", Offset: 8},
+		{FileId: "b", Offset: 0},
+		{FileId: "m", IsChunkManifest: true},
+		{FileId: "c", Offset: 4},
+	}
+
+	tests := []struct {
+		name  string
+		batch int
+		want  []string
+	}{
+		{name: "batch disabled", batch: 0},
+		{name: "below batch", batch: 4},
+		{name: "equal to batch", batch: 3, want: []string{"b", "c", "a"}},
+		{name: "above batch", batch: 2, want: []string{"b", "c"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, fc := range foldable(data, tt.batch) {
+				got = append(got, fc.GetFileId())
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// testManifestChunk returns a filer_pb.FileChunk for the provided file ID, offset, and size, which references its
+// content using both filer_pb.FileChunk.FileId and filer_pb.FileChunk.Fid as chunks written by SeaweedFS do.
+func testManifestChunk(t *testing.T, fileID string, offset int64, size uint64) *filer_pb.FileChunk {
+	t.Helper()
+
+	fid, err := parseFID(fileID)
+	require.NoError(t, err)
+	return &filer_pb.FileChunk{
+		Fid:          fid,
+		FileId:       fileID,
+		ModifiedTsNs: 1,
+		Offset:       offset,
+		Size:         size,
+	}
+}
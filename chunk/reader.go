@@ -8,10 +8,11 @@ import (
 	"io"
 	"math/rand"
 	"net/url"
+	"sort"
 	"sync"
 
+	"github.com/transientvariable/anchor"
 	"github.com/transientvariable/anchor/net/http"
-	"github.com/transientvariable/hold"
 	"github.com/transientvariable/lettuce/support"
 	"github.com/transientvariable/log-go"
 
@@ -31,10 +32,13 @@ var (
 	rcPool = sync.Pool{
 		New: func() any { return &rc{} },
 	}
+
+	// zeros is the content written for holes.
+	zeros = make([]byte, 32*anchor.KiB)
 )
 
 type rc struct {
-	chunk   Chunk
+	view    View
 	content *bytebufferpool.ByteBuffer
 	discard int
 	err     error
@@ -54,6 +58,7 @@ type Reader struct {
 	ctxParent context.Context
 	err       error
 	findVols  FindVolumes
+	manifests map[string][]byte
 	mutex     sync.RWMutex
 	offset    int64
 	path      string
@@ -61,6 +66,7 @@ type Reader struct {
 	queue     <-chan chan *rc
 	queueSize int
 	size      int64
+	views     []View
 }
 
 // NewReader creates a new Reader using the provided FindVolumes function and Chunks.
@@ -74,11 +80,12 @@ func NewReader(findVols FindVolumes, chunks *Chunks, option ...func(*Reader)) (*
 	}
 
 	r := &Reader{
-		buf:      &bytes.Buffer{},
-		chunks:   chunks,
-		findVols: findVols,
-		path:     chunks.Path(),
-		size:     chunks.Size(),
+		buf:       &bytes.Buffer{},
+		chunks:    chunks,
+		findVols:  findVols,
+		manifests: make(map[string][]byte),
+		path:      chunks.Path(),
+		size:      chunks.Size(),
 	}
 	for _, opt := range option {
 		opt(r)
@@ -97,7 +104,7 @@ func NewReader(findVols FindVolumes, chunks *Chunks, option ...func(*Reader)) (*
 	}
 	r.ctx, r.ctxCancel = context.WithCancel(r.ctxParent)
 
-	if r.chunks.Len() > 0 {
+	if r.size > 0 {
 		if err := r.init(0); err != nil {
 			return nil, fmt.Errorf("chunks_reader: %w", err)
 		}
@@ -219,16 +226,25 @@ func (r *Reader) Seek(off int64, whence int) (int64, error) {
 	return off, nil
 }
 
-func (r *Reader) buffer(ctx context.Context, iter hold.Iterator[Chunk]) <-chan chan *rc {
+// buffer queues the content for the provided views starting at off. Holes are queued in parts no larger than Size, so
+// that reading a sparse file does not buffer the content for an entire hole at once.
+func (r *Reader) buffer(ctx context.Context, views []View, off int64) <-chan chan *rc {
 	queue := make(chan chan *rc)
 	go func() {
 		defer close(queue)
-		for iter.HasNext() {
-			c, err := iter.Next()
-			select {
-			case queue <- r.acquireChunk(ctx, c, 0, err):
-			case <-ctx.Done():
-				return
+		for _, v := range views {
+			for start := max(v.Offset.Start, off); start < v.Offset.End; {
+				p := View{Chunk: v.Chunk, Offset: Offset{Start: start, End: v.Offset.End}}
+				if p.IsHole() {
+					p.Offset.End = min(start+Size, v.Offset.End)
+				}
+
+				select {
+				case queue <- r.acquireChunk(ctx, p):
+				case <-ctx.Done():
+					return
+				}
+				start = p.Offset.End
 			}
 		}
 	}()
@@ -236,11 +252,32 @@ func (r *Reader) buffer(ctx context.Context, iter hold.Iterator[Chunk]) <-chan c
 }
 
 func (r *Reader) get(ctx context.Context, c Chunk) (*bytebufferpool.ByteBuffer, error) {
-	locs, err := r.find(ctx, c)
+	b, loc, err := r.fetch(ctx, c)
 	if err != nil {
 		return nil, err
 	}
 
+	// The content for a chunk trimmed by Chunks.Truncate extends beyond the size of the chunk.
+	if w := int64(b.Len()); w < c.Size() {
+		releaseByteBuffer(b)
+		return nil, &ContentLengthError{
+			Op:            "get",
+			Chunk:         c,
+			ContentLength: w,
+			Location:      loc,
+			Path:          r.path,
+		}
+	}
+	return b, nil
+}
+
+// fetch retrieves the content for the provided Chunk from one of the volumes containing it.
+func (r *Reader) fetch(ctx context.Context, c Chunk) (*bytebufferpool.ByteBuffer, url.URL, error) {
+	locs, err := r.find(ctx, c)
+	if err != nil {
+		return nil, url.URL{}, err
+	}
+
 	var (
 		loc    url.URL
 		volIdx int
@@ -251,11 +288,15 @@ func (r *Reader) get(ctx context.Context, c Chunk) (*bytebufferpool.ByteBuffer,
 		loc = locs[int(uint(rand.Intn(len(locs)-1)))]
 	}
 
-	req, err := gohttp.NewRequest(http.MethodGet, loc.String(), nil)
+	req, err := gohttp.NewRequestWithContext(ctx, http.MethodGet, loc.String(), nil)
 	if err != nil {
-		return nil, err
+		return nil, loc, err
 	}
 
+	// Requesting gzip explicitly disables transparent decompression by the http.Client, so content compressed by the
+	// volume server and content compressed before upload are handled the same way by decode.
+	req.Header.Set(http.HeaderAcceptEncoding, "gzip")
+
 	resp, err := http.DoWithRetry(httpClient(), req)
 	defer func(resp *gohttp.Response) {
 		if resp != nil && resp.Body != nil {
@@ -265,81 +306,93 @@ func (r *Reader) get(ctx context.Context, c Chunk) (*bytebufferpool.ByteBuffer,
 		}
 	}(resp)
 	if err != nil {
-		return nil, err
+		return nil, loc, err
 	}
 
 	switch resp.StatusCode {
 	case gohttp.StatusOK, gohttp.StatusPartialContent:
 		break
 	case gohttp.StatusRequestedRangeNotSatisfiable:
-		return nil, fmt.Errorf("request failed %s: %w", req.URL.String(), ErrInvalidRange)
+		return nil, loc, fmt.Errorf("request failed %s: %w", req.URL.String(), ErrInvalidRange)
 	default:
-		return nil, errors.New(fmt.Sprintf("request failed %s: %s", req.URL.String(), resp.Status))
+		return nil, loc, errors.New(fmt.Sprintf("request failed %s: %s", req.URL.String(), resp.Status))
+	}
+
+	bufSize := r.chunks.ChunkSizeMax()
+	if bufSize <= 0 || bufSize > Size {
+		bufSize = Size
 	}
 
 	b := acquireByteBuffer()
-	buf := support.AcquireBufferN(int(r.chunks.ChunkSizeMax()))
+	buf := support.AcquireBufferN(int(bufSize))
 	defer support.ReleaseBuffer(buf)
 
-	w, err := io.CopyBuffer(b, resp.Body, buf)
-	if err != nil {
+	if _, err := io.CopyBuffer(b, resp.Body, buf); err != nil {
 		releaseByteBuffer(b)
-		return nil, err
+		return nil, loc, err
 	}
 
-	if w != c.Size() {
+	if err := decode(c, b, resp.Header.Get(http.HeaderContentEncoding)); err != nil {
 		releaseByteBuffer(b)
-		return nil, &ContentLengthError{
-			Op:            "get",
-			Chunk:         c,
-			ContentLength: w,
-			Location:      loc,
-			Path:          r.path,
-		}
+		return nil, loc, fmt.Errorf("decode %s: %w", c.FileID(), err)
 	}
-	return b, nil
+	return b, loc, nil
 }
 
-func (r *Reader) init(off int64) error {
-	if r.ctxCancel != nil {
-		r.ctxCancel()
-	}
-
-	cks, err := r.chunks.List()
-	if err != nil {
-		return err
-	}
-
-	c, err := cks.ValueAt(0)
-	if err != nil {
-		return err
+// decode replaces the content retrieved for the provided Chunk with its plaintext form if the Chunk is encrypted, and
+// its decompressed form if the Chunk is compressed or the content was compressed by the volume server using the
+// provided encoding.
+//
+// Encrypted content is compressed before it is encrypted, so it is decrypted first.
+func decode(c Chunk, b *bytebufferpool.ByteBuffer, encoding string) error {
+	key := c.PB().GetCipherKey()
+	compressed := encoding == "gzip" || c.PB().GetIsCompressed()
+	if len(key) == 0 && !compressed {
+		return nil
 	}
 
-	if off > 0 && off <= r.size {
-		c, err = r.chunks.AtOffset(off)
-		if err != nil {
+	content := b.Bytes()
+	var err error
+	if len(key) > 0 {
+		if content, err = decrypt(content, key); err != nil {
 			return err
 		}
 	}
 
-	iter := cks.Iterate()
-	if c.Position() > 0 {
-		r.position = c.Position()
-		if err := discard(iter, c.Position()); err != nil {
+	if compressed {
+		if content, err = decompress(content); err != nil {
 			return err
 		}
 	}
+	b.Reset()
+	_, err = b.Write(content)
+	return err
+}
 
-	r.ctx, r.ctxCancel = context.WithCancel(r.ctxParent)
-	c, err = iter.Next()
-	rc := <-r.acquireChunk(r.ctx, c, off, err)
-	if _, err := r.read(rc, r.buf); err != nil {
+func (r *Reader) init(off int64) error {
+	if r.ctxCancel != nil {
+		r.ctxCancel()
+	}
+
+	// Chunks may have been added since the Reader was created (e.g. a file opened for reading and writing), so the
+	// views are refreshed each time the Reader is positioned.
+	cks, err := resolveManifests(r.ctxParent, r.chunks.Values(), r.manifest)
+	if err != nil {
 		return err
 	}
+	r.size = r.chunks.Size()
+	r.views = views(cks, r.size)
+	r.ctx, r.ctxCancel = context.WithCancel(r.ctxParent)
 
+	i := sort.Search(len(r.views), func(i int) bool { return r.views[i].Offset.End > off })
 	r.offset = off
-	if iter.HasNext() {
-		r.queue = r.buffer(r.ctx, iter)
+	r.position = i
+	r.queue = r.buffer(r.ctx, r.views[i:], off)
+
+	if c, ok := <-r.queue; ok {
+		if _, err := r.read(<-c, r.buf); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -356,7 +409,7 @@ func (r *Reader) find(ctx context.Context, c Chunk) ([]url.URL, error) {
 
 	var locations []url.URL
 	for _, vol := range vols {
-		if v, loc, err := location(c, vol); err == nil && v {
+		if v, loc, err := location(ctx, c, vol); err == nil && v {
 			locations = append(locations, loc)
 		}
 	}
@@ -367,14 +420,14 @@ func (r *Reader) find(ctx context.Context, c Chunk) ([]url.URL, error) {
 	return locations, nil
 }
 
-func location(c Chunk, vol url.URL) (bool, url.URL, error) {
+func location(ctx context.Context, c Chunk, vol url.URL) (bool, url.URL, error) {
 	path, err := url.JoinPath(vol.Path, c.FileID())
 	if err != nil {
 		return false, vol, err
 	}
 	vol.Path = path
 
-	req, err := gohttp.NewRequest(http.MethodHead, vol.String(), nil)
+	req, err := gohttp.NewRequestWithContext(ctx, http.MethodHead, vol.String(), nil)
 	if err != nil {
 		return false, vol, err
 	}
@@ -397,6 +450,23 @@ func location(c Chunk, vol url.URL) (bool, url.URL, error) {
 	return false, vol, nil
 }
 
+// manifest returns the content of the provided manifest Chunk. The content is cached, since manifests are resolved
+// each time the Reader is positioned.
+func (r *Reader) manifest(ctx context.Context, c Chunk) ([]byte, error) {
+	if b, ok := r.manifests[c.FileID()]; ok {
+		return b, nil
+	}
+
+	b, _, err := r.fetch(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	defer releaseByteBuffer(b)
+
+	r.manifests[c.FileID()] = bytes.Clone(b.Bytes())
+	return r.manifests[c.FileID()], nil
+}
+
 func (r *Reader) read(c *rc, w *bytes.Buffer) (int64, error) {
 	if c == nil {
 		return 0, nil
@@ -418,23 +488,31 @@ func (r *Reader) read(c *rc, w *bytes.Buffer) (int64, error) {
 	return n, nil
 }
 
-// acquireChunk returns an internal chunk used for Reader operations.
-func (r *Reader) acquireChunk(ctx context.Context, c Chunk, off int64, err error) chan *rc {
+// acquireChunk returns an internal chunk used for Reader operations containing the content for the provided View.
+func (r *Reader) acquireChunk(ctx context.Context, v View) chan *rc {
 	chunk := make(chan *rc)
 	go func() {
 		defer close(chunk)
 		rc := rcPool.Get().(*rc)
-		rc.chunk = c
-		if err != nil {
-			rc.err = err
+		rc.view = v
+
+		if v.IsHole() {
+			rc.content = acquireByteBuffer()
+			for n := v.Offset.Length(); n > 0 && rc.err == nil; n -= int64(len(zeros)) {
+				_, rc.err = rc.content.Write(zeros[:min(n, int64(len(zeros)))])
+			}
+			chunk <- rc
+			return
 		}
 
-		if rc.err == nil {
-			rc.content, rc.err = r.get(ctx, rc.chunk)
-			if rc.err == nil && rc.content.Len() > 0 && off > 0 {
+		rc.content, rc.err = r.get(ctx, v.Chunk)
+		if rc.err == nil && rc.content.Len() > 0 {
+			start := v.ChunkOffset()
+			end := v.ChunkOffset() + v.Offset.Length()
+			if start > 0 || end < int64(rc.content.Len()) {
 				b := rc.content.Bytes()
 				rc.content.Reset()
-				_, rc.err = rc.content.Write(b[off-rc.chunk.Offset().Start:])
+				_, rc.err = rc.content.Write(b[start:end])
 			}
 		}
 		chunk <- rc
@@ -445,7 +523,7 @@ func (r *Reader) acquireChunk(ctx context.Context, c Chunk, off int64, err error
 // releaseChunk adds the internal Reader chunk back to the pool.
 func (r *Reader) releaseChunk(c *rc) {
 	if c != nil {
-		c.chunk = Chunk{}
+		c.view = View{}
 		if c.content != nil {
 			releaseByteBuffer(c.content)
 			c.content = nil
@@ -464,15 +542,6 @@ func (r *Reader) setErr(err error) error {
 	return r.err
 }
 
-func discard(iter hold.Iterator[Chunk], p int) error {
-	for i := 0; iter.HasNext() && i < p; i++ {
-		if _, err := iter.Next(); err != nil {
-			return err
-		}
-	}
-	return nil
-}
-
 // WithReaderContext ...
 func WithReaderContext(ctx context.Context) func(*Reader) {
 	return func(r *Reader) {
@@ -0,0 +1,53 @@
+package chunk
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/url"
+	"runtime"
+	"testing"
+
+	"github.com/transientvariable/anchor"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReaderSparse(t *testing.T) {
+	const size = 1 << 40
+
+	c, err := NewChunks("/sparse")
+	require.NoError(t, err)
+
+	_, err = c.Truncate(size)
+	require.NoError(t, err)
+
+	var before runtime.MemStats
+	runtime.ReadMemStats(&before)
+
+	r, err := NewReader(func(context.Context, string, string) ([]url.URL, error) {
+		t.Fatal("volumes must not be retrieved for holes")
+		return nil, nil
+	}, c)
+	require.NoError(t, err)
+	defer r.Close()
+
+	b := make([]byte, 3*Size/2)
+	_, err = io.ReadFull(r, b)
+	require.NoError(t, err)
+	assert.True(t, bytes.Equal(make([]byte, len(b)), b))
+
+	off, err := r.Seek(size-Size-10, io.SeekStart)
+	require.NoError(t, err)
+	assert.Equal(t, int64(size-Size-10), off)
+
+	b, err = io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, Size+10, len(b))
+	assert.True(t, bytes.Equal(make([]byte, len(b)), b))
+
+	var after runtime.MemStats
+	runtime.ReadMemStats(&after)
+	assert.Less(t, after.TotalAlloc-before.TotalAlloc, uint64(256*anchor.MiB))
+}
@@ -0,0 +1,83 @@
+package chunk
+
+import (
+	"sort"
+)
+
+// View represents the portion of a file's content that is visible for a Chunk.
+//
+// Chunks may overlap when parts of a file are rewritten, in which case the content of the most recently modified Chunk
+// (see filer_pb.FileChunk.ModifiedTsNs) is visible. A View with a zero Chunk represents a hole (i.e. a range of the
+// file that has not been written), which reads as zeros.
+type View struct {
+	Chunk  Chunk
+	Offset Offset
+}
+
+// ChunkOffset returns the position of the start of the View relative to the start of the Chunk content.
+func (v View) ChunkOffset() int64 {
+	return v.Offset.Start - v.Chunk.Offset().Start
+}
+
+// IsHole reports whether the View represents a range of the file that has not been written.
+func (v View) IsHole() bool {
+	return v.Chunk.PB() == nil
+}
+
+// views returns the Views for the provided data chunks ordered by offset, covering the range [0, size).
+func views(chunks []Chunk, size int64) []View {
+	sort.SliceStable(chunks, func(i int, j int) bool {
+		ti, tj := chunks[i].PB().GetModifiedTsNs(), chunks[j].PB().GetModifiedTsNs()
+		if ti != tj {
+			return ti < tj
+		}
+		return chunks[i].Position() < chunks[j].Position()
+	})
+
+	var visible []View
+	for _, c := range chunks {
+		if c.Size() <= 0 {
+			continue
+		}
+
+		next := make([]View, 0, len(visible)+2)
+		for _, v := range visible {
+			if v.Offset.End <= c.Offset().Start || v.Offset.Start >= c.Offset().End {
+				next = append(next, v)
+				continue
+			}
+
+			if v.Offset.Start < c.Offset().Start {
+				next = append(next, View{Chunk: v.Chunk, Offset: Offset{Start: v.Offset.Start, End: c.Offset().Start}})
+			}
+
+			if v.Offset.End > c.Offset().End {
+				next = append(next, View{Chunk: v.Chunk, Offset: Offset{Start: c.Offset().End, End: v.Offset.End}})
+			}
+		}
+		visible = append(next, View{Chunk: c, Offset: c.Offset()})
+	}
+	sort.Slice(visible, func(i int, j int) bool { return visible[i].Offset.Start < visible[j].Offset.Start })
+
+	var (
+		vs  []View
+		off int64
+	)
+	for _, v := range visible {
+		if v.Offset.Start >= size {
+			break
+		}
+		v.Offset.End = min(v.Offset.End, size)
+
+		if v.Offset.Start > off {
+			vs = append(vs, View{Offset: Offset{Start: off, End: v.Offset.Start}})
+		}
+		vs = append(vs, v)
+		off = v.Offset.End
+	}
+
+	if off < size {
+		vs = append(vs, View{Offset: Offset{Start: off, End: size}})
+	}
+	return vs
+}
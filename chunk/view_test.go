@@ -0,0 +1,130 @@
+package chunk
+
+import (
+	"testing"
+
+	"github.com/transientvariable/lettuce/pb/filer_pb"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testView describes an expected View using the file ID of the Chunk, which is empty for a hole.
+type testView struct {
+	fileID string
+	start  int64
+	end    int64
+}
+
+func TestViews(t *testing.T) {
+	tests := []struct {
+		name   string
+		chunks []*filer_pb.FileChunk
+		size   int64
+		want   []testView
+	}{
+		{
+			name: "no chunks",
+			size: 10,
+			want: []testView{{start: 0, end: 10}},
+		},
+		{
+			name:   "single chunk",
+			chunks: []*filer_pb.FileChunk{testChunk("a", 0, 10, 1)},
+			size:   10,
+			want:   []testView{{fileID: "a", start: 0, end: 10}},
+		},
+		{
+			name:   "hole between chunks",
+			chunks: []*filer_pb.FileChunk{testChunk("a", 0, 4, 1), testChunk("b", 6, 4, 1)},
+			size:   10,
+			want:   []testView{{fileID: "a", start: 0, end: 4}, {start: 4, end: 6}, {fileID: "b", start: 6, end: 10}},
+		},
+		{
+			name:   "newer chunk overwrites middle",
+			chunks: []*filer_pb.FileChunk{testChunk("a", 0, 10, 1), testChunk("b", 3, 3, 2)},
+			size:   10,
+			want: []testView{
+				{fileID: "a", start: 0, end: 3},
+				{fileID: "b", start: 3, end: 6},
+				{fileID: "a", start: 6, end: 10},
+			},
+		},
+		{
+			name:   "older chunk hidden",
+			chunks: []*filer_pb.FileChunk{testChunk("a", 0, 10, 2), testChunk("b", 3, 3, 1)},
+			size:   10,
+			want:   []testView{{fileID: "a", start: 0, end: 10}},
+		},
+		{
+			name:   "newer chunk overlaps start",
+			chunks: []*filer_pb.FileChunk{testChunk("a", 2, 8, 1), testChunk("b", 0, 4, 2)},
+			size:   10,
+			want:   []testView{{fileID: "b", start: 0, end: 4}, {fileID: "a", start: 4, end: 10}},
+		},
+		{
+			name:   "same time ordered by position",
+			chunks: []*filer_pb.FileChunk{testChunk("a", 0, 10, 1), testChunk("b", 2, 2, 1)},
+			size:   10,
+			want: []testView{
+				{fileID: "a", start: 0, end: 2},
+				{fileID: "b", start: 2, end: 4},
+				{fileID: "a", start: 4, end: 10},
+			},
+		},
+		{
+			name:   "size truncates chunk",
+			chunks: []*filer_pb.FileChunk{testChunk("a", 0, 10, 1), testChunk("b", 6, 4, 1)},
+			size:   5,
+			want:   []testView{{fileID: "a", start: 0, end: 5}},
+		},
+		{
+			name:   "size extends past chunks",
+			chunks: []*filer_pb.FileChunk{testChunk("a", 0, 4, 1)},
+			size:   8,
+			want:   []testView{{fileID: "a", start: 0, end: 4}, {start: 4, end: 8}},
+		},
+		{
+			name:   "empty chunk ignored",
+			chunks: []*filer_pb.FileChunk{testChunk("a", 0, 4, 1), testChunk("b", 2, 0, 2)},
+			size:   4,
+			want:   []testView{{fileID: "a", start: 0, end: 4}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chunks := make([]Chunk, len(tt.chunks))
+			for i, c := range tt.chunks {
+				var err error
+				chunks[i], err = NewChunk(c, WithPosition(uint(i)))
+				require.NoError(t, err)
+			}
+
+			var got []testView
+			for _, v := range views(chunks, tt.size) {
+				assert.Equal(t, v.IsHole(), v.Chunk.FileID() == "")
+				got = append(got, testView{fileID: v.Chunk.FileID(), start: v.Offset.Start, end: v.Offset.End})
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestViewChunkOffset(t *testing.T) {
+	c, err := NewChunk(testChunk("a", 100, 50, 1))
+	require.NoError(t, err)
+
+	v := View{Chunk: c, Offset: Offset{Start: 120, End: 150}}
+	assert.Equal(t, int64(20), v.ChunkOffset())
+}
+
+// testChunk returns a filer_pb.FileChunk with the provided file ID, offset, size, and modification time.
+func testChunk(fileID string, offset int64, size uint64, modifiedTsNs int64) *filer_pb.FileChunk {
+	return &filer_pb.FileChunk{
+		FileId:       fileID,
+		ModifiedTsNs: modifiedTsNs,
+		Offset:       offset,
+		Size:         size,
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"github.com/transientvariable/anchor/net/http"
+	"github.com/transientvariable/lettuce/pb/filer_pb"
 	"github.com/transientvariable/lettuce/support"
 	"github.com/transientvariable/log-go"
 	"github.com/valyala/bytebufferpool"
@@ -39,26 +40,43 @@ type wc struct {
 	offset  int64
 }
 
+// AppendEntry defines the signature for the function used by a Writer in append mode for committing chunks to the
+// entry represented by the provided path.
+type AppendEntry func(context.Context, string, ...*filer_pb.FileChunk) error
+
+// InlineContent defines the signature for the function used by a Writer for storing content inline with the entry
+// rather than uploading it as a chunk.
+type InlineContent func([]byte) error
+
 // AssignVolume ...
 type AssignVolume func(context.Context, string) (string, url.URL, error)
 
 // Writer ...
 type Writer struct {
-	assignVol AssignVolume
-	buf       *bytes.Buffer
-	chunks    *Chunks
-	chunkSize int
-	closed    bool
-	ctx       context.Context
-	ctxCancel context.CancelFunc
-	ctxParent context.Context
-	err       error
-	mutex     sync.Mutex
-	offset    int64
-	path      string
-	queue     chan []byte
-	wgBuf     sync.WaitGroup
-	wgWrite   sync.WaitGroup
+	appendFn   AppendEntry
+	assignVol  AssignVolume
+	buf        *bytes.Buffer
+	bufErr     error
+	chunked    bool
+	chunks     *Chunks
+	chunkSize  int
+	closed     bool
+	compress   Compression
+	encrypt    bool
+	inlineFn   InlineContent
+	inlineSize int
+	mfstBatch  int
+	ctx        context.Context
+	ctxCancel  context.CancelFunc
+	ctxParent  context.Context
+	err        error
+	mutex      sync.Mutex
+	offset     int64
+	path       string
+	pos        int64
+	queue      chan []byte
+	wgBuf      sync.WaitGroup
+	wgWrite    sync.WaitGroup
 }
 
 // NewWriter ...
@@ -80,10 +98,15 @@ func NewWriter(path string, assignVol AssignVolume, option ...func(*Writer)) (*W
 		w.chunkSize = Size
 	}
 
+	if w.mfstBatch <= 0 {
+		w.mfstBatch = ManifestBatch
+	}
+
 	if w.ctxParent == nil {
 		w.ctxParent = context.Background()
 	}
 
+	w.pos = w.offset
 	w.ctx, w.ctxCancel = context.WithCancel(w.ctxParent)
 	w.queue = make(chan []byte)
 	w.buffer(w.ctx, w.queue)
@@ -107,8 +130,12 @@ func (w *Writer) Close() error {
 	if !w.closed {
 		w.closed = true
 		w.err = errors.New("chunk_writer: already closed")
+		if w.inlineFn != nil && !w.chunked && w.offset == 0 && w.buf.Len() > 0 && w.buf.Len() <= w.inlineSize {
+			return w.inlineFn(bytes.Clone(w.buf.Bytes()))
+		}
+
 		if w.buf.Len() > 0 {
-			if err := w.write(w.ctx, w.buf); err != nil {
+			if err := w.write(w.ctx, w.buf, false); err != nil {
 				return err
 			}
 		}
@@ -131,35 +158,124 @@ func (w *Writer) Write(b []byte) (int, error) {
 	w.wgBuf.Add(1)
 	w.queue <- b
 	w.wgBuf.Wait()
+	if w.bufErr != nil {
+		w.err = w.bufErr
+		return 0, w.err
+	}
+	w.pos += int64(len(b))
 	return len(b), nil
 }
 
+// Flush writes any buffered content as a new chunk, regardless of the chunk size for the Writer.
+func (w *Writer) Flush() error {
+	if w == nil {
+		return ErrInvalidOp
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.flush()
+}
+
+// Offset returns the offset within the entry content at which the next write will occur.
+func (w *Writer) Offset() int64 {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.pos
+}
+
+// Seek sets the offset for the next write to offset, interpreted according to whence: io.SeekStart means relative to
+// the start of the entry content and io.SeekCurrent means relative to the current offset. Since the Writer does not
+// track the size of the entry content, io.SeekEnd is not supported.
+//
+// Any buffered content is flushed before the offset is changed. Chunks written at an offset that overlaps existing
+// content take precedence over the existing content when read.
+func (w *Writer) Seek(offset int64, whence int) (int64, error) {
+	if w == nil {
+		return 0, ErrInvalidOp
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	switch whence {
+	case io.SeekStart:
+		// no-ops
+	case io.SeekCurrent:
+		offset += w.pos
+	default:
+		return 0, fmt.Errorf("chunk_writer: invalid whence: %d", whence)
+	}
+
+	if offset < 0 {
+		return 0, errors.New("chunk_writer: negative position")
+	}
+
+	if offset != w.pos {
+		if err := w.flush(); err != nil {
+			return 0, err
+		}
+		w.offset = offset
+		w.pos = offset
+	}
+	return offset, nil
+}
+
+// buffer starts the goroutine that buffers content from the queue and writes chunks once the buffered content reaches
+// the chunk size for the Writer. The result for each queued slice is recorded in bufErr before the sender is released.
+// Once an error occurs, including cancellation of ctx, the queue is still drained so that senders never block, but the
+// remaining content is discarded and the error is reported for each slice.
 func (w *Writer) buffer(ctx context.Context, queue <-chan []byte) {
 	go func() {
+		var err error
 		for b := range queue {
-			select {
-			case <-ctx.Done():
-				return
-			default:
-				if _, err := w.buf.Write(b); err != nil {
-					w.setErr(err)
-					w.wgBuf.Done()
-					return
-				}
-				w.wgBuf.Done()
-
-				if w.buf.Len() >= w.chunkSize {
-					if err := w.write(ctx, w.buf); err != nil {
-						w.setErr(err)
-						return
-					}
-				}
+			if err == nil {
+				err = ctx.Err()
+			}
+
+			if err == nil {
+				err = w.buffered(ctx, b)
 			}
+			w.bufErr = err
+			w.wgBuf.Done()
 		}
 	}()
 }
 
-func (w *Writer) write(ctx context.Context, buf *bytes.Buffer) error {
+// buffered adds b to the buffered content, writing chunks if the buffered content reaches the chunk size for the
+// Writer. A nil slice writes any buffered content regardless of the chunk size.
+func (w *Writer) buffered(ctx context.Context, b []byte) error {
+	if b == nil {
+		return w.write(ctx, w.buf, true)
+	}
+
+	if _, err := w.buf.Write(b); err != nil {
+		return err
+	}
+
+	if w.buf.Len() >= w.chunkSize {
+		return w.write(ctx, w.buf, false)
+	}
+	return nil
+}
+
+// flush signals the buffer to write any buffered content and waits for the write to complete. The nil slice sent to the
+// queue is used as the signal, since Write never queues empty slices.
+func (w *Writer) flush() error {
+	if w.err != nil {
+		return w.err
+	}
+
+	w.wgBuf.Add(1)
+	w.queue <- nil
+	w.wgBuf.Wait()
+	if w.bufErr != nil {
+		w.err = w.bufErr
+	}
+	return w.err
+}
+
+func (w *Writer) write(ctx context.Context, buf *bytes.Buffer, flush bool) error {
 	w.wgWrite.Add(1)
 	defer w.wgWrite.Done()
 
@@ -174,13 +290,13 @@ func (w *Writer) write(ctx context.Context, buf *bytes.Buffer) error {
 			return err
 		}
 
-		if err := w.writeChunk(c); err != nil {
+		if err := w.writeChunk(ctx, c); err != nil {
 			return err
 		}
 		w.offset += int64(n)
 	}
 
-	if w.closed && buf.Len() > 0 {
+	if (w.closed || flush) && buf.Len() > 0 {
 		c := w.acquireChunk(ctx, buf.Len(), w.offset)
 		if c.err != nil {
 			return c.err
@@ -191,7 +307,7 @@ func (w *Writer) write(ctx context.Context, buf *bytes.Buffer) error {
 			return err
 		}
 
-		if err := w.writeChunk(c); err != nil {
+		if err := w.writeChunk(ctx, c); err != nil {
 			return err
 		}
 		w.offset += int64(n)
@@ -208,10 +324,10 @@ func (w *Writer) write(ctx context.Context, buf *bytes.Buffer) error {
 	return nil
 }
 
-func (w *Writer) writeChunk(c *wc) error {
+func (w *Writer) writeChunk(ctx context.Context, c *wc) error {
 	defer w.releaseChunk(c)
 	ts := time.Now()
-	r, err := w.upload(c)
+	r, err := w.upload(ctx, c)
 	if err != nil {
 		return err
 	}
@@ -220,26 +336,106 @@ func (w *Writer) writeChunk(c *wc) error {
 	if err != nil {
 		return err
 	}
+	w.chunked = true
+
+	if w.appendFn != nil {
+		return w.appendFn(ctx, w.path, fc)
+	}
 
 	if w.chunks != nil {
 		if _, err := w.chunks.Add(fc); err != nil {
 			return err
 		}
+		return w.fold(ctx)
+	}
+	return nil
+}
+
+// fold replaces data chunks with a manifest chunk once the number of data chunks reaches the manifest batch size for
+// the Writer, which keeps the metadata for large files small.
+func (w *Writer) fold(ctx context.Context) error {
+	fcs, err := w.chunks.PB()
+	if err != nil {
+		return err
+	}
+
+	data := foldable(fcs, w.mfstBatch)
+	if data == nil {
+		return nil
+	}
+
+	b, mfst, err := manifest(data)
+	if err != nil {
+		return err
+	}
+
+	c := w.acquireChunk(ctx, len(b), mfst.GetOffset())
+	defer w.releaseChunk(c)
+	if c.err != nil {
+		return c.err
+	}
+	copy(c.content, b)
+
+	r, err := w.upload(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	fc, err := r.FileChunk(c.fileID, mfst.GetOffset(), mfst.GetModifiedTsNs())
+	if err != nil {
+		return err
+	}
+	fc.IsChunkManifest = true
+	fc.Size = mfst.GetSize()
+
+	log.Trace("[chunk:writer] folding chunks into manifest",
+		log.String("file_id", fc.GetFileId()),
+		log.Int("chunks", len(data)),
+		log.String("path", w.path))
+
+	w.chunks.Remove(data...)
+	if _, err := w.chunks.Add(fc); err != nil {
+		return err
 	}
 	return nil
 }
 
-func (w *Writer) upload(c *wc) (UploadResult, error) {
+func (w *Writer) upload(ctx context.Context, c *wc) (UploadResult, error) {
 	buf := acquireByteBuffer()
 	defer releaseByteBuffer(buf)
 
-	var r UploadResult
-	ct, err := w.createFormFile(c.content, c.loc, buf)
+	var (
+		r        UploadResult
+		content  = c.content
+		encoding string
+		key      []byte
+	)
+	if w.compress != CompressionNone {
+		b, err := compress(w.compress, c.content)
+		if err != nil {
+			return r, err
+		}
+		content = b
+		encoding = string(w.compress)
+	}
+
+	// Encrypted content is opaque to the volume server, so the content encoding is only recorded in the chunk metadata.
+	if w.encrypt {
+		b, k, err := encrypt(content)
+		if err != nil {
+			return r, err
+		}
+		content = b
+		encoding = ""
+		key = k
+	}
+
+	ct, err := w.createFormFile(content, c.loc, encoding, buf)
 	if err != nil {
 		return r, err
 	}
 
-	req, err := gohttp.NewRequest(http.MethodPost, c.loc.String(), bytes.NewReader(buf.Bytes()))
+	req, err := gohttp.NewRequestWithContext(ctx, http.MethodPost, c.loc.String(), bytes.NewReader(buf.Bytes()))
 	if err != nil {
 		return r, err
 	}
@@ -266,13 +462,28 @@ func (w *Writer) upload(c *wc) (UploadResult, error) {
 	if err != nil {
 		return r, err
 	}
+
+	// The volume server only reports the original size for gzip content, so the size of the chunk is always set to the
+	// size of the uncompressed content.
+	if w.compress != CompressionNone {
+		r.GZip = 1
+		r.Size = uint32(len(c.content))
+	}
+
+	if w.encrypt {
+		r.CipherKey = key
+		r.Size = uint32(len(c.content))
+	}
 	return r, nil
 }
 
-func (w *Writer) createFormFile(c []byte, loc url.URL, buf *bytebufferpool.ByteBuffer) (string, error) {
+func (w *Writer) createFormFile(c []byte, loc url.URL, encoding string, buf *bytebufferpool.ByteBuffer) (string, error) {
 	h := make(textproto.MIMEHeader)
 	h.Set(http.HeaderContentDisposition, fmt.Sprintf(`form-data; name="file"; filename="%s"`, escapeQuotes(w.path)))
 	h.Set(http.HeaderIdempotencyKey, loc.String())
+	if encoding != "" {
+		h.Set(http.HeaderContentEncoding, encoding)
+	}
 
 	mw := multipart.NewWriter(buf)
 	cw, err := mw.CreatePart(h)
@@ -291,14 +502,6 @@ func (w *Writer) createFormFile(c []byte, loc url.URL, buf *bytebufferpool.ByteB
 	return ct, nil
 }
 
-func (w *Writer) setErr(err error) {
-	w.mutex.Lock()
-	defer w.mutex.Unlock()
-	if w.err == nil && err != nil {
-		w.err = err
-	}
-}
-
 // acquireChunk returns an internal chunk used for Writer operations.
 func (w *Writer) acquireChunk(ctx context.Context, s int, off int64) *wc {
 	c := wcPool.Get().(*wc)
@@ -330,6 +533,16 @@ func escapeQuotes(s string) string {
 	return quoteEscaper.Replace(s)
 }
 
+// WithWriterAppend sets the function used for committing chunks, which puts the Writer in append mode.
+//
+// In append mode, chunks are not added to the Chunks for the Writer, since their final offsets are assigned by the
+// filer when they are committed.
+func WithWriterAppend(fn AppendEntry) func(*Writer) {
+	return func(w *Writer) {
+		w.appendFn = fn
+	}
+}
+
 // WithWriterChunks ...
 func WithWriterChunks(chunks *Chunks) func(*Writer) {
 	return func(w *Writer) {
@@ -344,9 +557,48 @@ func WithWriterChunkSize(size uint) func(*Writer) {
 	}
 }
 
+// WithWriterCompression sets the Compression used for chunk content before it is uploaded.
+func WithWriterCompression(c Compression) func(*Writer) {
+	return func(w *Writer) {
+		w.compress = c
+	}
+}
+
 // WithWriterContext ...
 func WithWriterContext(ctx context.Context) func(*Writer) {
 	return func(w *Writer) {
 		w.ctxParent = ctx
 	}
 }
+
+// WithWriterEncryption sets whether chunk content is encrypted before it is uploaded. Each chunk is encrypted using
+// AES-GCM with a random key, which is stored in the chunk metadata (see filer_pb.FileChunk.CipherKey).
+func WithWriterEncryption(enable bool) func(*Writer) {
+	return func(w *Writer) {
+		w.encrypt = enable
+	}
+}
+
+// WithWriterInline sets the function used for storing content inline with the entry, which is used instead of uploading
+// a chunk if all the content written is no larger than size.
+func WithWriterInline(size int, fn InlineContent) func(*Writer) {
+	return func(w *Writer) {
+		w.inlineFn = fn
+		w.inlineSize = size
+	}
+}
+
+// WithWriterManifestBatch sets the number of data chunks that are folded into a manifest chunk. Defaults to
+// ManifestBatch.
+func WithWriterManifestBatch(size uint) func(*Writer) {
+	return func(w *Writer) {
+		w.mfstBatch = int(size)
+	}
+}
+
+// WithWriterOffset sets the offset within the entry content at which the Writer starts writing.
+func WithWriterOffset(offset int64) func(*Writer) {
+	return func(w *Writer) {
+		w.offset = offset
+	}
+}
@@ -0,0 +1,245 @@
+package chunk
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/transientvariable/lettuce/pb/filer_pb"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testUpload is the content of a chunk uploaded to a testVolume.
+type testUpload struct {
+	content  []byte
+	encoding string
+}
+
+// testVolume is a volume server that records the chunks uploaded to it.
+type testVolume struct {
+	*httptest.Server
+	mutex   sync.Mutex
+	uploads []testUpload
+}
+
+func newTestVolume(t *testing.T) *testVolume {
+	t.Helper()
+
+	v := &testVolume{}
+	v.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		p, err := multipart.NewReader(r.Body, params["boundary"]).NextPart()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		b, err := io.ReadAll(p)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		v.mutex.Lock()
+		v.uploads = append(v.uploads, testUpload{content: b, encoding: p.Header.Get("Content-Encoding")})
+		v.mutex.Unlock()
+
+		w.WriteHeader(http.StatusCreated)
+		_, _ = fmt.Fprintf(w, `{"name":"chunk","size":%d}`, len(b))
+	}))
+	t.Cleanup(v.Close)
+	return v
+}
+
+// assign assigns a file ID on the testVolume for each chunk.
+func (v *testVolume) assign(context.Context, string) (string, url.URL, error) {
+	u, err := url.Parse(v.URL + "/3,01637037d6")
+	if err != nil {
+		return "", url.URL{}, err
+	}
+	return "3,01637037d6", *u, nil
+}
+
+func TestWriterInline(t *testing.T) {
+	content := strings.Repeat("lettuce", 8)
+
+	tests := []struct {
+		name        string
+		writes      []string
+		inlineSize  int
+		wantInline  string
+		wantUploads int
+	}{
+		{name: "fits inline", writes: []string{content}, inlineSize: len(content), wantInline: content},
+		{
+			name:        "exceeds inline size",
+			writes:      []string{content, "x"},
+			inlineSize:  len(content),
+			wantUploads: 1,
+		},
+		{name: "inline content moved to chunk", writes: []string{content}, wantUploads: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := newTestVolume(t)
+			chunks, err := NewChunks("/test/file")
+			require.NoError(t, err)
+
+			opts := []func(*Writer){WithWriterChunks(chunks), WithWriterCompression(CompressionGzip)}
+
+			var inline []byte
+			if tt.inlineSize > 0 {
+				opts = append(opts, WithWriterInline(tt.inlineSize, func(b []byte) error {
+					inline = b
+					return nil
+				}))
+			}
+
+			w, err := NewWriter("/test/file", v.assign, opts...)
+			require.NoError(t, err)
+
+			var want string
+			for _, s := range tt.writes {
+				_, err := w.Write([]byte(s))
+				require.NoError(t, err)
+				want += s
+			}
+			require.NoError(t, w.Close())
+
+			assert.Equal(t, tt.wantInline, string(inline))
+			require.Len(t, v.uploads, tt.wantUploads)
+			if tt.wantUploads == 0 {
+				assert.Zero(t, chunks.Len())
+				return
+			}
+
+			// Content that is not stored inline is uploaded using the compression for the Writer.
+			assert.Equal(t, string(CompressionGzip), v.uploads[0].encoding)
+			b, err := decompress(v.uploads[0].content)
+			require.NoError(t, err)
+			assert.Equal(t, want, string(b))
+
+			fcs, err := chunks.PB()
+			require.NoError(t, err)
+			require.Len(t, fcs, 1)
+			assert.True(t, fcs[0].GetIsCompressed())
+			assert.Equal(t, uint64(len(want)), fcs[0].GetSize())
+		})
+	}
+}
+
+func TestWriterContextCanceled(t *testing.T) {
+	v := newTestVolume(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	w, err := NewWriter("/test/file", v.assign, WithWriterContext(ctx), WithWriterChunkSize(4))
+	require.NoError(t, err)
+
+	_, err = w.Write([]byte("lettuce"))
+	assert.ErrorIs(t, err, context.Canceled)
+	_, err = w.Write([]byte("lettuce"))
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.ErrorIs(t, w.Flush(), context.Canceled)
+	assert.ErrorIs(t, w.Close(), context.Canceled)
+	assert.Empty(t, v.uploads)
+}
+
+func TestWriterAppend(t *testing.T) {
+	v := newTestVolume(t)
+	chunks, err := NewChunks("/test/file")
+	require.NoError(t, err)
+
+	var appended []*filer_pb.FileChunk
+	appendFn := func(_ context.Context, path string, fcs ...*filer_pb.FileChunk) error {
+		assert.Equal(t, "/test/file", path)
+		appended = append(appended, fcs...)
+		return nil
+	}
+
+	w, err := NewWriter("/test/file",
+		v.assign,
+		WithWriterAppend(appendFn),
+		WithWriterChunks(chunks),
+		WithWriterChunkSize(4),
+		WithWriterOffset(100))
+	require.NoError(t, err)
+
+	_, err = w.Write([]byte("abcdefghij"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(110), w.Offset())
+	require.NoError(t, w.Close())
+
+	tests := []struct {
+		offset  int64
+		size    uint64
+		content string
+	}{
+		{offset: 100, size: 4, content: "abcd"},
+		{offset: 104, size: 4, content: "efgh"},
+		{offset: 108, size: 2, content: "ij"},
+	}
+
+	require.Len(t, appended, len(tests))
+	require.Len(t, v.uploads, len(tests))
+	for i, tt := range tests {
+		assert.Equal(t, tt.offset, appended[i].GetOffset(), "chunk %d", i)
+		assert.Equal(t, tt.size, appended[i].GetSize(), "chunk %d", i)
+		assert.Equal(t, tt.content, string(v.uploads[i].content), "chunk %d", i)
+	}
+
+	// Appended chunks are committed by the filer, so they are not added to the Chunks for the Writer.
+	assert.Zero(t, chunks.Len())
+}
+
+func TestWriterFlush(t *testing.T) {
+	v := newTestVolume(t)
+	chunks, err := NewChunks("/test/file")
+	require.NoError(t, err)
+
+	w, err := NewWriter("/test/file", v.assign, WithWriterChunks(chunks), WithWriterChunkSize(8))
+	require.NoError(t, err)
+
+	_, err = w.Write([]byte("abc"))
+	require.NoError(t, err)
+	assert.Empty(t, v.uploads)
+
+	// Flushing writes the buffered content even though it is smaller than the chunk size.
+	require.NoError(t, w.Flush())
+	require.Len(t, v.uploads, 1)
+	assert.Equal(t, "abc", string(v.uploads[0].content))
+
+	// Flushing without buffered content does not write a chunk.
+	require.NoError(t, w.Flush())
+	require.Len(t, v.uploads, 1)
+
+	_, err = w.Write([]byte("de"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	require.Len(t, v.uploads, 2)
+	assert.Equal(t, "de", string(v.uploads[1].content))
+
+	fcs, err := chunks.PB()
+	require.NoError(t, err)
+	require.Len(t, fcs, 2)
+	assert.Equal(t, int64(0), fcs[0].GetOffset())
+	assert.Equal(t, uint64(3), fcs[0].GetSize())
+	assert.Equal(t, int64(3), fcs[1].GetOffset())
+	assert.Equal(t, uint64(2), fcs[1].GetSize())
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/transientvariable/lettuce/client"
 	"github.com/transientvariable/lettuce/cluster/filer"
@@ -21,25 +22,75 @@ type volumeInfo struct {
 	volumes []*volume.Volume
 }
 
-// Truncate removes data associated with the provided name, sets the size of the entry metadata to 0, and updates the
-// modification time.
-func (c *Cluster) Truncate(ctx context.Context, name string) (*filer.Entry, error) {
+// Truncate changes the size of the entry for the provided name and updates the modification time.
+//
+// If the entry is shrunk, data past the new size is deleted. If the entry is extended, the content between the old and
+// new size reads as zeros. Data is not deleted if the entry is one of several hard links, since the volume data is
+// shared with the other links and is left for the filer to reclaim once it is no longer referenced.
+func (c *Cluster) Truncate(ctx context.Context, name string, size int64) (*filer.Entry, error) {
+	if size < 0 {
+		return nil, &client.Error{Op: "truncate", Err: fmt.Errorf("invalid size: %d", size)}
+	}
+
 	entry, err := c.Filer().Stat(ctx, name)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := c.TruncateEntry(ctx, entry, size); err != nil {
+		return entry, err
+	}
+	return entry, nil
+}
+
+// TruncateEntry is like Truncate, but operates on the provided filer.Entry, which is updated in place.
+func (c *Cluster) TruncateEntry(ctx context.Context, entry *filer.Entry, size int64) error {
+	if size < 0 {
+		return &client.Error{Op: "truncate", Err: fmt.Errorf("invalid size: %d", size)}
+	}
+
 	if entry.IsDir() {
-		return entry, nil
+		return nil
 	}
 
 	log.Trace("[cluster] truncating entry",
 		log.Int("chunks", entry.Chunks().Len()),
-		log.String("name", entry.Name()))
+		log.Int("links", entry.Links()),
+		log.String("name", entry.Name()),
+		log.Int64("size", size))
 
-	volumes, err := c.mapVolumes(ctx, entry)
+	removed, err := entry.Truncate(size)
 	if err != nil {
-		return entry, &client.Error{Op: "truncate", Err: err}
+		return &client.Error{Op: "truncate", Err: err}
+	}
+	entry.SetModTime(time.Now())
+
+	if err = c.Filer().Update(ctx, entry); err != nil {
+		return &client.Error{Op: "truncate", Err: err}
+	}
+
+	if len(removed) > 0 && entry.Links() <= 1 {
+		fids := make([]string, len(removed))
+		for i, fc := range removed {
+			fids[i] = fc.GetFileId()
+		}
+
+		if err := c.deleteNeedles(ctx, entry, fids); err != nil {
+			return err
+		}
+	}
+
+	log.Trace("[cluster] entry truncated",
+		log.String("name", entry.Name()),
+		log.Time("mod_time", entry.ModTime()))
+
+	return nil
+}
+
+func (c *Cluster) deleteNeedles(ctx context.Context, entry *filer.Entry, fids []string) error {
+	volumes, err := c.mapVolumes(ctx, fids)
+	if err != nil {
+		return &client.Error{Op: "truncate", Err: err}
 	}
 
 	if len(volumes) > 0 {
@@ -50,35 +101,22 @@ func (c *Cluster) Truncate(ctx context.Context, name string) (*filer.Entry, erro
 		for id, fids := range volumes {
 			v, err := c.Volume(id.Host())
 			if err != nil {
-				return entry, &client.Error{Op: "truncate", Err: err}
+				return &client.Error{Op: "truncate", Err: err}
 			}
 
 			r, err := v.Delete(ctx, fids...)
 			if err != nil {
-				return entry, &client.Error{Op: "truncate", Err: err}
+				return &client.Error{Op: "truncate", Err: err}
 			}
 
 			log.Trace(fmt.Sprintf("[cluster] deletion result: %s\n", r))
 		}
 	}
-
-	entry.Truncate()
-	if err = c.Filer().Update(ctx, entry); err != nil {
-		return entry, &client.Error{Op: "truncate", Err: err}
-	}
-
-	log.Trace("[cluster] entry truncated",
-		log.String("name", entry.Name()),
-		log.Time("mod_time", entry.ModTime()))
-
-	return entry, nil
+	return nil
 }
 
-func (c *Cluster) mapVolumes(ctx context.Context, entry *filer.Entry) (map[client.ID][]string, error) {
-	fids, err := readFileIDs(ctx, entry)
-	if err != nil {
-		return nil, err
-	}
+func (c *Cluster) mapVolumes(ctx context.Context, fileIDs []string) (map[client.ID][]string, error) {
+	fids := emitFileIDs(ctx, fileIDs)
 	volumes := make(chan volumeInfo)
 
 	var wg sync.WaitGroup
@@ -99,15 +137,11 @@ func (c *Cluster) mapVolumes(ctx context.Context, entry *filer.Entry) (map[clien
 	vm := make(map[client.ID][]string)
 	for vi := range volumes {
 		if vi.err != nil {
-			return vm, err
+			return vm, vi.err
 		}
 
 		for _, v := range vi.volumes {
-			fids, ok := vm[v.ID()]
-			if !ok {
-				vm[v.ID()] = []string{vi.fileID}
-			}
-			fids = append(fids, vi.fileID)
+			vm[v.ID()] = append(vm[v.ID()], vi.fileID)
 		}
 	}
 	return vm, nil
@@ -140,12 +174,7 @@ func (c *Cluster) findVolumes(ctx context.Context, fids <-chan string, volumes c
 	}
 }
 
-func readFileIDs(ctx context.Context, entry *filer.Entry) (<-chan string, error) {
-	fids, err := entry.FileIDs()
-	if err != nil {
-		return nil, err
-	}
-
+func emitFileIDs(ctx context.Context, fids []string) <-chan string {
 	log.Trace("[cluster] emitting file IDs", log.Int("size", len(fids)))
 
 	out := make(chan string)
@@ -159,5 +188,5 @@ func readFileIDs(ctx context.Context, entry *filer.Entry) (<-chan string, error)
 			}
 		}
 	}()
-	return out, nil
+	return out
 }
@@ -1,7 +1,13 @@
 package filer
 
 import (
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"maps"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -11,9 +17,14 @@ import (
 	"github.com/transientvariable/lettuce/client"
 	"github.com/transientvariable/lettuce/pb/filer_pb"
 
+	gofs "io/fs"
+
 	json "github.com/json-iterator/go"
 )
 
+// chmodBits are the file mode bits that can be changed using Entry.SetMode.
+const chmodBits = gofs.ModePerm | gofs.ModeSetuid | gofs.ModeSetgid | gofs.ModeSticky
+
 // Collection is container for properties that represent a `bucket` within the context of a SeaweedFS filer.
 type Collection struct {
 	GID  uint32 `json:"gid"`
@@ -49,6 +60,13 @@ func newEntry(path Path, pbEntry *filer_pb.Entry) (*Entry, error) {
 	if err != nil {
 		return nil, err
 	}
+
+	// The file size exceeds the end of the last chunk for files extended with Truncate.
+	if size := int64(pbEntry.GetAttributes().GetFileSize()); !pbEntry.GetIsDirectory() && size > cks.Size() {
+		if _, err := cks.Truncate(size); err != nil {
+			return nil, err
+		}
+	}
 	e.chunks = cks
 	return e, nil
 }
@@ -58,7 +76,26 @@ func (e *Entry) Chunks() *chunk.Chunks {
 	return e.chunks
 }
 
+// ClearMd5 removes the MD5 digest of the content of the Entry, which is no longer valid once the content is changed,
+// and returns whether a digest was removed. The digest is removed automatically when the content or chunks of the Entry
+// are changed, so ClearMd5 is only needed for content changed by the filer (e.g. using Filer.Append) when the Entry is
+// saved using Filer.Update.
+func (e *Entry) ClearMd5() bool {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	attrs := e.pbEntry.GetAttributes()
+	if len(attrs.GetMd5()) == 0 {
+		return false
+	}
+	attrs.Md5 = nil
+	return true
+}
+
 // Collection returns the Entry Collection.
+//
+// The Collection is only known for an Entry created with a Placement that sets the collection (see WithCollection),
+// since the filer does not record the collection for an entry.
 func (e *Entry) Collection() Collection {
 	if e.collection == nil {
 		return Collection{}
@@ -66,6 +103,87 @@ func (e *Entry) Collection() Collection {
 	return *e.collection
 }
 
+// Content returns the content of the Entry if it is stored inline rather than in chunks, otherwise nil is returned.
+//
+// The size of the Entry may exceed the length of the content if the Entry has been extended, in which case the
+// remaining bytes read as zeros.
+func (e *Entry) Content() []byte {
+	return e.pbEntry.GetContent()
+}
+
+// Crtime returns the creation time for the Entry.
+func (e *Entry) Crtime() time.Time {
+	if e.pbEntry.GetAttributes() != nil {
+		return time.Unix(e.pbEntry.GetAttributes().GetCrtime(), 0)
+	}
+	return time.Time{}
+}
+
+// DeleteExtended removes the extended attribute with the provided key from the Entry.
+func (e *Entry) DeleteExtended(key string) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	delete(e.pbEntry.GetExtended(), key)
+}
+
+// Extended returns the value of the extended attribute with the provided key, and whether the attribute is present.
+func (e *Entry) Extended(key string) ([]byte, bool) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	v, ok := e.pbEntry.GetExtended()[key]
+	return v, ok
+}
+
+// ExtendedKeys returns the sorted keys of the extended attributes for the Entry.
+func (e *Entry) ExtendedKeys() []string {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	return slices.Sorted(maps.Keys(e.pbEntry.GetExtended()))
+}
+
+// ETag returns the entity tag for the content of the Entry, which is computed the same way as by the SeaweedFS filer:
+// the MD5 digest of the content if it is known, otherwise the digest of the first chunk for single chunk entries, or
+// the digest of the chunk digests followed by the number of chunks for multi-chunk entries.
+//
+// The MD5 digest of the content (e.g. set for objects uploaded using the S3 gateway) is removed whenever the content
+// of the Entry is changed (see Entry.ClearMd5). The filer keeps the digest when chunks are appended using Filer.Append,
+// so it is ignored if a chunk was modified after the modification time of the Entry.
+func (e *Entry) ETag() string {
+	if m := e.pbEntry.GetAttributes().GetMd5(); len(m) > 0 && !e.appended() {
+		return hex.EncodeToString(m)
+	}
+
+	if c := e.pbEntry.GetContent(); len(c) > 0 || len(e.pbEntry.GetChunks()) == 0 {
+		m := md5.Sum(c)
+		return hex.EncodeToString(m[:])
+	}
+
+	chunks := e.pbEntry.GetChunks()
+	if len(chunks) == 1 {
+		return hex.EncodeToString(md5Digest(chunks[0].GetETag()))
+	}
+
+	var digests []byte
+	for _, c := range chunks {
+		digests = append(digests, md5Digest(c.GetETag())...)
+	}
+	m := md5.Sum(digests)
+	return fmt.Sprintf("%x-%d", m, len(chunks))
+}
+
+// Expires returns the time the Entry and its content are removed by the cluster, and whether the Entry expires. The
+// time is relative to the creation time of the Entry.
+func (e *Entry) Expires() (time.Time, bool) {
+	ttl := e.TTL()
+	if ttl <= 0 {
+		return time.Time{}, false
+	}
+	return e.Crtime().Add(ttl), true
+}
+
 // FileIDs returns the list containing the file ID for each chunk.
 func (e *Entry) FileIDs() ([]string, error) {
 	cks, err := e.Chunks().List()
@@ -95,11 +213,35 @@ func (e *Entry) GID() int32 {
 	return client.GID
 }
 
+// HardLinkID returns the ID shared by all entries that are hard links to the same content. If the Entry is not a hard
+// link, nil is returned.
+func (e *Entry) HardLinkID() []byte {
+	return e.pbEntry.GetHardLinkId()
+}
+
 // IsDir returns whether the Entry represents is a directory.
 func (e *Entry) IsDir() bool {
 	return e.pbEntry.GetIsDirectory()
 }
 
+// IsSymlink returns whether the Entry represents a symbolic link.
+func (e *Entry) IsSymlink() bool {
+	return e.SymlinkTarget() != ""
+}
+
+// Links returns the number of hard links to the content of the Entry.
+func (e *Entry) Links() int {
+	if len(e.HardLinkID()) == 0 || e.pbEntry.GetHardLinkCounter() <= 0 {
+		return 1
+	}
+	return int(e.pbEntry.GetHardLinkCounter())
+}
+
+// Mime returns the MIME type for the Entry, or an empty string if it is not known.
+func (e *Entry) Mime() string {
+	return e.pbEntry.GetAttributes().GetMime()
+}
+
 // ModTime returns the modification time for the Entry.
 func (e *Entry) ModTime() time.Time {
 	if e.pbEntry.GetAttributes() != nil {
@@ -108,6 +250,11 @@ func (e *Entry) ModTime() time.Time {
 	return time.Time{}
 }
 
+// Mode returns the file mode bits for the Entry.
+func (e *Entry) Mode() gofs.FileMode {
+	return gofs.FileMode(e.pbEntry.GetAttributes().GetFileMode())
+}
+
 // Name returns the name for the Entry.
 func (e *Entry) Name() string {
 	return e.Path().Name()
@@ -123,14 +270,106 @@ func (e *Entry) PB() *filer_pb.Entry {
 	return e.pbEntry
 }
 
+// SetContent sets the content of the Entry to be stored inline, replacing any existing chunks. Setting the content to
+// nil or an empty slice clears the inline content.
+func (e *Entry) SetContent(content []byte) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if e.pbEntry.GetIsDirectory() || e.pbEntry.GetAttributes() == nil {
+		return
+	}
+	e.pbEntry.Content = content
+	e.pbEntry.Chunks = nil
+	e.pbEntry.GetAttributes().FileSize = uint64(len(content))
+	e.pbEntry.GetAttributes().Md5 = nil
+	e.chunks.Clear()
+}
+
+// SetCrtime sets the creation time for the Entry.
+func (e *Entry) SetCrtime(t time.Time) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if attrs := e.pbEntry.GetAttributes(); attrs != nil {
+		attrs.Crtime = t.Unix()
+	}
+}
+
+// SetExtended sets the value of the extended attribute with the provided key for the Entry.
+func (e *Entry) SetExtended(key string, value []byte) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if e.pbEntry.Extended == nil {
+		e.pbEntry.Extended = make(map[string][]byte)
+	}
+	e.pbEntry.Extended[key] = value
+}
+
+// SetMode sets the permission bits for the Entry. Bits describing the type of the Entry are left unchanged.
+func (e *Entry) SetMode(mode gofs.FileMode) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if attrs := e.pbEntry.GetAttributes(); attrs != nil {
+		m := gofs.FileMode(attrs.GetFileMode())&^chmodBits | mode&chmodBits
+		attrs.FileMode = uint32(m)
+	}
+}
+
+// SetMime sets the MIME type for the Entry.
+func (e *Entry) SetMime(mime string) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if attrs := e.pbEntry.GetAttributes(); attrs != nil && !e.pbEntry.GetIsDirectory() {
+		attrs.Mime = mime
+	}
+}
+
+// SetModTime sets the modification time for the Entry.
+func (e *Entry) SetModTime(t time.Time) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if attrs := e.pbEntry.GetAttributes(); attrs != nil {
+		attrs.Mtime = t.Unix()
+	}
+}
+
+// SetOwner sets the user and group IDs for the Entry.
+func (e *Entry) SetOwner(uid uint32, gid uint32) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if attrs := e.pbEntry.GetAttributes(); attrs != nil {
+		attrs.Uid = uid
+		attrs.Gid = gid
+	}
+}
+
 // Size returns the size of the Entry.
+//
+// Chunks appended by the filer do not update the file size attribute, so the size is the greater of the attribute, the
+// inline content and the end of the last chunk.
 func (e *Entry) Size() int64 {
 	if !e.PB().GetIsDirectory() && e.PB().GetAttributes() != nil {
-		return int64(e.PB().GetAttributes().GetFileSize())
+		size := max(e.PB().GetAttributes().GetFileSize(), uint64(len(e.PB().GetContent())))
+		for _, c := range e.PB().GetChunks() {
+			size = max(size, uint64(c.GetOffset())+c.GetSize())
+		}
+		return int64(size)
 	}
 	return 0
 }
 
+// SymlinkTarget returns the path the Entry points to if it represents a symbolic link, otherwise an empty string is
+// returned.
+func (e *Entry) SymlinkTarget() string {
+	return e.pbEntry.GetAttributes().GetSymlinkTarget()
+}
+
 // ToMap returns a map of the Entry properties.
 func (e *Entry) ToMap() (map[string]any, error) {
 	var m map[string]any
@@ -140,18 +379,46 @@ func (e *Entry) ToMap() (map[string]any, error) {
 	return m, nil
 }
 
-// Truncate removes all chunks and sets the size to 0 if the Entry is not a directory.
+// Truncate changes the size of the Entry if it is not a directory, and returns the protobuf chunks that no longer
+// contain content for the Entry. See chunk.Chunks.Truncate for details.
 //
 // The same operations will be performed on the protobuf entry if present.
-func (e *Entry) Truncate() {
+func (e *Entry) Truncate(size int64) ([]*filer_pb.FileChunk, error) {
 	e.mutex.Lock()
 	defer e.mutex.Unlock()
 
-	if !e.pbEntry.GetIsDirectory() && e.pbEntry.GetAttributes() != nil {
-		e.pbEntry.GetAttributes().FileSize = 0
-		e.pbEntry.Chunks = e.pbEntry.Chunks[:0]
-		e.chunks.Clear()
+	if e.pbEntry.GetIsDirectory() || e.pbEntry.GetAttributes() == nil {
+		return nil, nil
+	}
+
+	// Content stored inline that is extended reads as zeros past the end of the content (see Entry.Content).
+	if c := e.pbEntry.GetContent(); len(c) > 0 {
+		if size < int64(len(c)) {
+			e.pbEntry.Content = c[:size]
+		}
+		e.pbEntry.GetAttributes().FileSize = uint64(size)
+		e.pbEntry.GetAttributes().Md5 = nil
+		return nil, nil
+	}
+
+	removed, err := e.chunks.Truncate(size)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := e.chunks.PB()
+	if err != nil {
+		return nil, err
 	}
+	e.pbEntry.Chunks = entries
+	e.pbEntry.GetAttributes().FileSize = uint64(size)
+	e.pbEntry.GetAttributes().Md5 = nil
+	return removed, nil
+}
+
+// TTL returns the time-to-live for the Entry, or zero if the Entry does not expire.
+func (e *Entry) TTL() time.Duration {
+	return time.Duration(e.pbEntry.GetAttributes().GetTtlSec()) * time.Second
 }
 
 // UID returns the group ID for the Entry.
@@ -176,9 +443,39 @@ func (e *Entry) String() string {
 	s["path"] = e.Path().String()
 	s["is_dir"] = e.IsDir()
 	s["size"] = e.Size()
+	if e.Links() > 1 {
+		s["links"] = e.Links()
+	}
+
+	if e.IsSymlink() {
+		s["symlink_target"] = e.SymlinkTarget()
+	}
+
+	if ttl := e.TTL(); ttl > 0 {
+		s["ttl"] = ttl.String()
+	}
 	return string(anchor.ToJSONFormatted(s))
 }
 
+// md5Digest returns the MD5 digest for the base64 encoded ETag of a chunk, or nil if the ETag is not base64 encoded.
+// appended returns whether a chunk of the Entry was modified after the modification time of the Entry, which is the
+// case for chunks appended using Filer.Append, since the filer does not update the modification time when appending.
+// The modification time is in seconds, so chunks appended within the same second are not detected.
+func (e *Entry) appended() bool {
+	mtime := (e.pbEntry.GetAttributes().GetMtime() + 1) * int64(time.Second)
+	return slices.ContainsFunc(e.pbEntry.GetChunks(), func(c *filer_pb.FileChunk) bool {
+		return c.GetModifiedTsNs() >= mtime
+	})
+}
+
+func md5Digest(etag string) []byte {
+	b, err := base64.StdEncoding.DecodeString(etag)
+	if err != nil {
+		return nil
+	}
+	return b
+}
+
 func (e *Entry) update(chunks *chunk.Chunks) error {
 	e.mutex.Lock()
 	defer e.mutex.Unlock()
@@ -196,6 +493,7 @@ func (e *Entry) update(chunks *chunk.Chunks) error {
 	if !pb.GetIsDirectory() && pb.GetAttributes() != nil && len(entries) > 0 {
 		attrs := e.pbEntry.GetAttributes()
 		attrs.FileSize = uint64(chunks.Size())
+		attrs.Md5 = nil
 		pb.Chunks = entries
 	}
 	return nil
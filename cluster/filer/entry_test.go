@@ -0,0 +1,148 @@
+package filer
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/transientvariable/lettuce/pb/filer_pb"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	// testETagA and testETagB are the chunk entity tags for the content "a" and "b", which are the base64 encoded MD5
+	// digests of the content.
+	testETagA = "DMF1ucDxtqgxw5niaXcmYQ=="
+	testETagB = "kutf/uauL+w61xx3dTFXjw=="
+)
+
+func TestEntryETag(t *testing.T) {
+	md5, err := hex.DecodeString("00112233445566778899aabbccddeeff")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		pbEntry *filer_pb.Entry
+		want    string
+	}{
+		{
+			name:    "empty",
+			pbEntry: &filer_pb.Entry{Attributes: &filer_pb.FuseAttributes{}},
+			want:    "d41d8cd98f00b204e9800998ecf8427e",
+		},
+		{
+			name:    "inline content",
+			pbEntry: &filer_pb.Entry{Attributes: &filer_pb.FuseAttributes{FileSize: 7}, Content: []byte("lettuce")},
+			want:    "8cbd191432b5f52b48497313f966a4f8",
+		},
+		{
+			name: "single chunk",
+			pbEntry: &filer_pb.Entry{
+				Attributes: &filer_pb.FuseAttributes{FileSize: 1},
+				Chunks:     []*filer_pb.FileChunk{{FileId: "3,01637037d6", ETag: testETagA, Size: 1}},
+			},
+			want: "0cc175b9c0f1b6a831c399e269772661",
+		},
+		{
+			name: "multiple chunks",
+			pbEntry: &filer_pb.Entry{
+				Attributes: &filer_pb.FuseAttributes{FileSize: 2},
+				Chunks: []*filer_pb.FileChunk{
+					{FileId: "3,01637037d6", ETag: testETagA, Size: 1},
+					{FileId: "3,02637037d6", ETag: testETagB, Offset: 1, Size: 1},
+				},
+			},
+			want: "96e024ba2074fe77e8e965ba43a704be-2",
+		},
+		{
+			name: "content digest",
+			pbEntry: &filer_pb.Entry{
+				Attributes: &filer_pb.FuseAttributes{FileSize: 1, Md5: md5},
+				Chunks:     []*filer_pb.FileChunk{{FileId: "3,01637037d6", ETag: testETagA, Size: 1}},
+			},
+			want: "00112233445566778899aabbccddeeff",
+		},
+		{
+			name: "content digest with chunk modified in same second",
+			pbEntry: &filer_pb.Entry{
+				Attributes: &filer_pb.FuseAttributes{FileSize: 1, Md5: md5, Mtime: 10},
+				Chunks: []*filer_pb.FileChunk{
+					{FileId: "3,01637037d6", ETag: testETagA, ModifiedTsNs: 10_900_000_000, Size: 1},
+				},
+			},
+			want: "00112233445566778899aabbccddeeff",
+		},
+		{
+			name: "content digest with appended chunk",
+			pbEntry: &filer_pb.Entry{
+				Attributes: &filer_pb.FuseAttributes{FileSize: 2, Md5: md5, Mtime: 10},
+				Chunks: []*filer_pb.FileChunk{
+					{FileId: "3,01637037d6", ETag: testETagA, ModifiedTsNs: 9_000_000_000, Size: 1},
+					{FileId: "3,02637037d6", ETag: testETagB, ModifiedTsNs: 11_000_000_000, Offset: 1, Size: 1},
+				},
+			},
+			want: "96e024ba2074fe77e8e965ba43a704be-2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := newEntry("/test/file", tt.pbEntry)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, e.ETag())
+		})
+	}
+}
+
+func TestEntryETagContentChanged(t *testing.T) {
+	md5, err := hex.DecodeString("00112233445566778899aabbccddeeff")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		change func(*Entry) error
+		want   string
+	}{
+		{
+			name:   "set content",
+			change: func(e *Entry) error { e.SetContent([]byte("lettuce")); return nil },
+			want:   "8cbd191432b5f52b48497313f966a4f8",
+		},
+		{
+			name: "add chunk",
+			change: func(e *Entry) error {
+				_, err := e.Chunks().Add(&filer_pb.FileChunk{FileId: "3,02637037d6", ETag: testETagB, Offset: 1, Size: 1})
+				return err
+			},
+			want: "96e024ba2074fe77e8e965ba43a704be-2",
+		},
+		{
+			name: "truncate",
+			change: func(e *Entry) error {
+				_, err := e.Truncate(0)
+				return err
+			},
+			want: "d41d8cd98f00b204e9800998ecf8427e",
+		},
+		{
+			name:   "clear digest",
+			change: func(e *Entry) error { assert.True(t, e.ClearMd5()); return nil },
+			want:   "0cc175b9c0f1b6a831c399e269772661",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := newEntry("/test/file", &filer_pb.Entry{
+				Attributes: &filer_pb.FuseAttributes{FileSize: 1, Md5: md5},
+				Chunks:     []*filer_pb.FileChunk{{FileId: "3,01637037d6", ETag: testETagA, ModifiedTsNs: 1, Size: 1}},
+			})
+			require.NoError(t, err)
+
+			require.NoError(t, tt.change(e))
+			assert.Equal(t, tt.want, e.ETag())
+			assert.False(t, e.ClearMd5())
+		})
+	}
+}
@@ -0,0 +1,16 @@
+package filer
+
+// Enumeration of errors that may be returned by a SeaweedFS filer API client.
+const (
+	ErrLockExpired = filerError("lock has expired")
+	ErrLocked      = filerError("lock is held by another owner")
+	ErrLockLost    = filerError("lock ownership lost")
+)
+
+// filerError defines the type for errors that may be returned by a SeaweedFS filer.
+type filerError string
+
+// Error returns the cause of a SeaweedFS filer error.
+func (e filerError) Error() string {
+	return string(e)
+}
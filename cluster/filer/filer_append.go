@@ -0,0 +1,47 @@
+package filer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/transientvariable/anchor"
+	"github.com/transientvariable/lettuce/client"
+	"github.com/transientvariable/lettuce/pb/filer_pb"
+	"github.com/transientvariable/log-go"
+
+	"google.golang.org/grpc/status"
+)
+
+// Append appends one or more chunks to the content of the named entry.
+//
+// The filer assigns the offset of each chunk relative to the current end of the entry when the request is processed,
+// so concurrent appends from different clients do not overwrite each other.
+func (f *Filer) Append(ctx context.Context, name string, chunks ...*filer_pb.FileChunk) error {
+	if len(chunks) == 0 {
+		return nil
+	}
+
+	p, err := f.path(name)
+	if err != nil {
+		return &client.Error{Op: "append", Client: f, Err: err}
+	}
+
+	log.Trace("[filer] append", log.String("path", p.String()), log.Int("chunks", len(chunks)))
+
+	req := &filer_pb.AppendToEntryRequest{
+		Directory: p.Dir(),
+		EntryName: p.Name(),
+		Chunks:    chunks,
+	}
+
+	log.Trace(fmt.Sprintf("[filer] append request: \n%s", anchor.ToJSONFormatted(req)))
+
+	if _, err := f.PB().AppendToEntry(ctx, req); err != nil {
+		if s, ok := status.FromError(err); ok {
+			return &client.Error{Op: "append", Client: f, Err: errors.New(s.Message())}
+		}
+		return &client.Error{Op: "append", Client: f, Err: err}
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"mime"
 	"path/filepath"
 	"time"
 
@@ -19,8 +20,34 @@ import (
 
 // Create creates a new Filer entry.
 //
-// If the operation is successful, an Entry will be returned representing the created entry.
-func (f *Filer) Create(ctx context.Context, name string, mode gofs.FileMode) (*Entry, error) {
+// If the operation is successful, an Entry will be returned representing the created entry. The provided options
+// define the Placement for the content of the entry (see WithCollection, WithTTL).
+func (f *Filer) Create(ctx context.Context,
+	name string,
+	mode gofs.FileMode,
+	options ...func(*Placement),
+) (*Entry, error) {
+	return f.create(ctx, name, mode, nil, options...)
+}
+
+// CreateWithContent is like Create, but stores the provided content inline with the entry, so that the entry and its
+// content are created using a single request (e.g. for configuration files read by other SeaweedFS components from
+// metadata events).
+func (f *Filer) CreateWithContent(ctx context.Context,
+	name string,
+	mode gofs.FileMode,
+	content []byte,
+	options ...func(*Placement),
+) (*Entry, error) {
+	return f.create(ctx, name, mode, content, options...)
+}
+
+func (f *Filer) create(ctx context.Context,
+	name string,
+	mode gofs.FileMode,
+	content []byte,
+	options ...func(*Placement),
+) (*Entry, error) {
 	e, err := f.Stat(ctx, name)
 	if err != nil {
 		if !errors.Is(err, gofs.ErrNotExist) {
@@ -43,40 +70,63 @@ func (f *Filer) Create(ctx context.Context, name string, mode gofs.FileMode) (*E
 		log.String("name", name),
 		log.String("path", path.String()))
 
+	p := newPlacement(options...)
 	attrs := &filer_pb.FuseAttributes{
 		Mtime:    time.Now().Unix(),
 		Crtime:   time.Now().Unix(),
 		FileMode: uint32(mode),
+		FileSize: uint64(len(content)),
 		Gid:      uint32(f.root.entry.GID()),
 		Uid:      uint32(f.root.entry.UID()),
+		TtlSec:   p.ttlSec(),
+	}
+
+	if mode&gofs.ModeDir == 0 {
+		attrs.Mime = mime.TypeByExtension(filepath.Ext(name))
 	}
 
 	pbEntry := &filer_pb.Entry{
 		Name:        path.Name(),
 		IsDirectory: mode&gofs.ModeDir != 0,
 		Attributes:  attrs,
+		Content:     content,
+	}
+
+	if err := f.createEntry(ctx, "create", path, pbEntry); err != nil {
+		return nil, err
+	}
+	e, err = f.NewEntry(filepath.Dir(name), pbEntry)
+	if err != nil {
+		return nil, err
 	}
 
+	if p.collection != "" {
+		e.collection = &Collection{GID: attrs.GetGid(), Name: p.collection, UID: attrs.GetUid()}
+	}
+	return e, nil
+}
+
+func (f *Filer) createEntry(ctx context.Context, op string, path Path, pbEntry *filer_pb.Entry) error {
 	req := &filer_pb.CreateEntryRequest{
 		Directory:  path.Dir(),
 		Entry:      pbEntry,
 		Signatures: []int32{f.signature},
 	}
 
-	log.Trace(fmt.Sprintf("[filer] create request: \n%s", anchor.ToJSONFormatted(req)))
+	log.Trace(fmt.Sprintf("[filer] %s request: \n%s", op, anchor.ToJSONFormatted(req)))
 
 	resp, err := f.PB().CreateEntry(ctx, req)
 	if err != nil {
 		if s, ok := status.FromError(err); ok {
-			return nil, &client.Error{Op: "create", Client: f, Err: errors.New(s.Message())}
+			return &client.Error{Op: op, Client: f, Err: errors.New(s.Message())}
 		}
-		return nil, &client.Error{Op: "create", Client: f, Err: err}
+		return &client.Error{Op: op, Client: f, Err: err}
 	}
 
-	log.Trace(fmt.Sprintf("[filer] create response: %s", resp.String()))
+	log.Trace(fmt.Sprintf("[filer] %s response: %s", op, resp.String()))
 
 	if respErr := resp.GetError(); respErr != "" {
-		return nil, &client.Error{Op: "create", Client: f, Err: errors.New(respErr)}
+		return &client.Error{Op: op, Client: f, Err: errors.New(respErr)}
 	}
-	return f.NewEntry(filepath.Dir(name), pbEntry)
+	return nil
 }
@@ -0,0 +1,71 @@
+package filer
+
+import (
+	"context"
+	"errors"
+
+	"github.com/transientvariable/lettuce/client"
+	"github.com/transientvariable/lettuce/pb/filer_pb"
+	"github.com/transientvariable/log-go"
+
+	"google.golang.org/grpc/status"
+)
+
+// KvDelete removes the value stored in the filer key-value store for the provided key.
+func (f *Filer) KvDelete(ctx context.Context, key []byte) error {
+	return f.kvPut(ctx, "kvDelete", key, nil)
+}
+
+// KvGet returns the value stored in the filer key-value store for the provided key. If there is no value for the key,
+// nil is returned.
+func (f *Filer) KvGet(ctx context.Context, key []byte) ([]byte, error) {
+	if len(key) == 0 {
+		return nil, &client.Error{Op: "kvGet", Client: f, Err: client.ErrInvalid}
+	}
+
+	log.Trace("[filer] kvGet", log.String("key", string(key)))
+
+	resp, err := f.PB().KvGet(ctx, &filer_pb.KvGetRequest{Key: key})
+	if err != nil {
+		if s, ok := status.FromError(err); ok {
+			return nil, &client.Error{Op: "kvGet", Client: f, Err: errors.New(s.Message())}
+		}
+		return nil, &client.Error{Op: "kvGet", Client: f, Err: err}
+	}
+
+	if respErr := resp.GetError(); respErr != "" {
+		return nil, &client.Error{Op: "kvGet", Client: f, Err: errors.New(respErr)}
+	}
+
+	if len(resp.GetValue()) == 0 {
+		return nil, nil
+	}
+	return resp.GetValue(), nil
+}
+
+// KvPut stores the value in the filer key-value store for the provided key. Storing an empty value removes the value
+// for the key.
+func (f *Filer) KvPut(ctx context.Context, key []byte, value []byte) error {
+	return f.kvPut(ctx, "kvPut", key, value)
+}
+
+func (f *Filer) kvPut(ctx context.Context, op string, key []byte, value []byte) error {
+	if len(key) == 0 {
+		return &client.Error{Op: op, Client: f, Err: client.ErrInvalid}
+	}
+
+	log.Trace("[filer] "+op, log.String("key", string(key)), log.Int("size", len(value)))
+
+	resp, err := f.PB().KvPut(ctx, &filer_pb.KvPutRequest{Key: key, Value: value})
+	if err != nil {
+		if s, ok := status.FromError(err); ok {
+			return &client.Error{Op: op, Client: f, Err: errors.New(s.Message())}
+		}
+		return &client.Error{Op: op, Client: f, Err: err}
+	}
+
+	if respErr := resp.GetError(); respErr != "" {
+		return &client.Error{Op: op, Client: f, Err: errors.New(respErr)}
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package filer
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/transientvariable/lettuce/client"
+	"github.com/transientvariable/lettuce/pb/filer_pb"
+	"github.com/transientvariable/log-go"
+
+	"google.golang.org/protobuf/proto"
+
+	gofs "io/fs"
+)
+
+const (
+	// hardLinkIDSize defines the number of random bytes used for a hard link ID.
+	hardLinkIDSize = 16
+
+	// hardLinkMarker is appended to hard link IDs to distinguish them from other keys in the filer store.
+	hardLinkMarker = '\x01'
+)
+
+// Link creates newname as a hard link to the entry oldname.
+//
+// Hard links are created using the same scheme as the SeaweedFS FUSE mount: the entry for oldname is assigned a hard
+// link ID if it does not already have one, the hard link counter is incremented, and a new entry sharing the same ID,
+// attributes, and content is created for newname. If the operation is successful, an Entry will be returned
+// representing newname.
+func (f *Filer) Link(ctx context.Context, oldname string, newname string) (*Entry, error) {
+	oe, err := f.Stat(ctx, oldname)
+	if err != nil {
+		return nil, err
+	}
+
+	if oe.IsDir() {
+		return nil, &client.Error{Op: "link", Client: f, Err: fmt.Errorf("%s: %w", oldname, gofs.ErrPermission)}
+	}
+
+	ne, err := f.Stat(ctx, newname)
+	if err != nil {
+		if !errors.Is(err, gofs.ErrNotExist) {
+			return nil, err
+		}
+	}
+
+	if ne != nil {
+		return ne, &client.Error{Op: "link", Client: f, Err: fmt.Errorf("%s: %w", newname, gofs.ErrExist)}
+	}
+
+	path, err := f.path(newname)
+	if err != nil {
+		return nil, &client.Error{Op: "link", Client: f, Err: err}
+	}
+
+	pb := oe.PB()
+	if len(pb.GetHardLinkId()) == 0 {
+		id, err := newHardLinkID()
+		if err != nil {
+			return nil, &client.Error{Op: "link", Client: f, Err: err}
+		}
+		pb.HardLinkId = id
+		pb.HardLinkCounter = 1
+	}
+	pb.HardLinkCounter++
+
+	log.Trace("[filer] link",
+		log.String("old_name", oldname),
+		log.String("new_name", newname),
+		log.Int("links", int(pb.GetHardLinkCounter())))
+
+	if err := f.Update(ctx, oe); err != nil {
+		return nil, err
+	}
+
+	pbEntry := proto.Clone(pb).(*filer_pb.Entry)
+	pbEntry.Name = path.Name()
+	if err := f.createEntry(ctx, "link", path, pbEntry); err != nil {
+		pb.HardLinkCounter--
+		return nil, errors.Join(err, f.Update(ctx, oe))
+	}
+	return f.NewEntry(filepath.Dir(newname), pbEntry)
+}
+
+func newHardLinkID() ([]byte, error) {
+	id := make([]byte, hardLinkIDSize, hardLinkIDSize+1)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	return append(id, hardLinkMarker), nil
+}
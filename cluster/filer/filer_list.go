@@ -0,0 +1,82 @@
+package filer
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/transientvariable/lettuce/client"
+	"github.com/transientvariable/lettuce/pb/filer_pb"
+	"github.com/transientvariable/log-go"
+
+	"google.golang.org/grpc/status"
+)
+
+// ListOptions defines the options for listing the entries in a directory using Filer.List.
+type ListOptions struct {
+	// Inclusive sets whether the entry named StartFrom is included in the listing.
+	Inclusive bool
+
+	// Limit sets the maximum number of entries returned. If zero, at most the directory listing limit of the filer (set
+	// using -dirListLimit) are returned, so callers that need every entry must set a limit or page through the
+	// directory using StartFrom.
+	Limit uint32
+
+	// Prefix restricts the listing to entries with names that start with the prefix.
+	Prefix string
+
+	// StartFrom sets the name of the entry the listing starts after (or at, see Inclusive). Entries are listed in
+	// lexical order by name.
+	StartFrom string
+}
+
+// List returns the entries in the named directory in lexical order by name using the provided ListOptions.
+func (f *Filer) List(ctx context.Context, name string, opts ListOptions) ([]*Entry, error) {
+	path, err := f.path(name)
+	if err != nil {
+		return nil, &client.Error{Op: "list", Client: f, Err: err}
+	}
+
+	log.Trace("[filer] list",
+		log.String("name", name),
+		log.String("path", path.String()),
+		log.String("prefix", opts.Prefix),
+		log.String("start_from", opts.StartFrom),
+		log.Bool("inclusive", opts.Inclusive),
+		log.Int("limit", int(opts.Limit)))
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := f.PB().ListEntries(ctx, &filer_pb.ListEntriesRequest{
+		Directory:          path.String(),
+		Prefix:             opts.Prefix,
+		StartFromFileName:  opts.StartFrom,
+		InclusiveStartFrom: opts.Inclusive,
+		Limit:              opts.Limit,
+	})
+	if err != nil {
+		return nil, &client.Error{Op: "list", Client: f, Err: err}
+	}
+
+	var entries []*Entry
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return entries, nil
+			}
+
+			if s, ok := status.FromError(err); ok {
+				return entries, &client.Error{Op: "list", Client: f, Err: errors.New(s.Message())}
+			}
+			return entries, &client.Error{Op: "list", Client: f, Err: err}
+		}
+
+		e, err := f.NewEntry(path.String(), resp.GetEntry())
+		if err != nil {
+			return entries, err
+		}
+		entries = append(entries, e)
+	}
+}
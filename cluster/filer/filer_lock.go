@@ -0,0 +1,151 @@
+package filer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/transientvariable/lettuce/client"
+	"github.com/transientvariable/lettuce/pb/filer_pb"
+	"github.com/transientvariable/log-go"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// lockRetryInterval defines the interval between attempts to acquire a lock that is held by another owner.
+	lockRetryInterval = time.Second
+)
+
+// Lock acquires the distributed lock with the provided name for owner, blocking until the lock is acquired or the
+// context is done.
+//
+// The lock expires unless it is renewed within ttl, which the returned Lock does in the background until it is
+// released (see Lock.Release). If the lock cannot be renewed, ownership is reported as lost (see Lock.Lost).
+func (f *Filer) Lock(ctx context.Context, name string, ttl time.Duration, owner string) (*Lock, error) {
+	if name = strings.TrimSpace(name); name == "" {
+		return nil, &client.Error{Op: "lock", Client: f, Err: fmt.Errorf("lock name is required: %w", client.ErrInvalid)}
+	}
+
+	if ttl < time.Second {
+		return nil, &client.Error{Op: "lock", Client: f, Err: fmt.Errorf("lock ttl is less than 1s: %w", client.ErrInvalid)}
+	}
+
+	log.Trace("[filer] lock", log.String("name", name), log.String("owner", owner), log.String("ttl", ttl.String()))
+
+	for {
+		acquired := time.Now()
+		token, err := f.TryLock(ctx, name, ttl, owner)
+		if err == nil {
+			return newLock(f, name, ttl, owner, token, acquired), nil
+		}
+
+		if !errors.Is(err, ErrLocked) {
+			return nil, err
+		}
+
+		select {
+		case <-time.After(lockRetryInterval):
+		case <-ctx.Done():
+			return nil, &client.Error{Op: "lock", Client: f, Err: ctx.Err()}
+		}
+	}
+}
+
+// TryLock makes a single attempt to acquire the distributed lock with the provided name for owner, and returns the
+// renewal token for the lock. The lock expires unless it is renewed within ttl using TryRenew.
+//
+// The error ErrLocked is returned if the lock is held by another owner.
+func (f *Filer) TryLock(ctx context.Context, name string, ttl time.Duration, owner string) (string, error) {
+	return f.lock(ctx, "lock", name, ttl, owner, "")
+}
+
+// TryRenew extends the distributed lock with the provided name and renewal token by ttl, and returns the new renewal
+// token for the lock.
+//
+// The error ErrLocked is returned if the lock has expired and is now held by another owner, and the error
+// ErrLockExpired is returned if the lock has expired or the renewal token is not valid for the lock.
+func (f *Filer) TryRenew(ctx context.Context,
+	name string,
+	ttl time.Duration,
+	owner string,
+	token string,
+) (string, error) {
+	return f.lock(ctx, "renew", name, ttl, owner, token)
+}
+
+// Unlock releases the distributed lock with the provided name and renewal token.
+func (f *Filer) Unlock(ctx context.Context, name string, token string) error {
+	log.Trace("[filer] unlock", log.String("name", name))
+
+	resp, err := f.PB().DistributedUnlock(ctx, &filer_pb.UnlockRequest{
+		Name:       name,
+		RenewToken: token,
+	})
+	if err != nil {
+		if s, ok := status.FromError(err); ok {
+			return &client.Error{Op: "unlock", Client: f, Err: errors.New(s.Message())}
+		}
+		return &client.Error{Op: "unlock", Client: f, Err: err}
+	}
+
+	if respErr := resp.GetError(); respErr != "" {
+		return &client.Error{Op: "unlock", Client: f, Err: errors.New(respErr)}
+	}
+	return nil
+}
+
+// LockOwner returns the owner of the distributed lock with the provided name, and whether the lock is held.
+func (f *Filer) LockOwner(ctx context.Context, name string) (string, bool, error) {
+	resp, err := f.PB().FindLockOwner(ctx, &filer_pb.FindLockOwnerRequest{Name: name})
+	if err != nil {
+		s, ok := status.FromError(err)
+		if !ok {
+			return "", false, &client.Error{Op: "lockOwner", Client: f, Err: err}
+		}
+
+		if s.Code() == codes.NotFound {
+			return "", false, nil
+		}
+		return "", false, &client.Error{Op: "lockOwner", Client: f, Err: errors.New(s.Message())}
+	}
+	return resp.GetOwner(), resp.GetOwner() != "", nil
+}
+
+func (f *Filer) lock(ctx context.Context,
+	op string,
+	name string,
+	ttl time.Duration,
+	owner string,
+	token string,
+) (string, error) {
+	resp, err := f.PB().DistributedLock(ctx, &filer_pb.LockRequest{
+		Name:          name,
+		SecondsToLock: int64((ttl + time.Second - 1) / time.Second),
+		RenewToken:    token,
+		Owner:         owner,
+	})
+	if err != nil {
+		if s, ok := status.FromError(err); ok {
+			return "", &client.Error{Op: op, Client: f, Err: errors.New(s.Message())}
+		}
+		return "", &client.Error{Op: op, Client: f, Err: err}
+	}
+
+	if respErr := resp.GetError(); respErr != "" {
+		if lo := resp.GetLockOwner(); lo != "" {
+			return "", &client.Error{Op: op, Client: f, Err: fmt.Errorf("%s: %w: %s", name, ErrLocked, lo)}
+		}
+
+		// The errors reported by the filer for renewal tokens that are not valid (e.g. "lock: non-empty token on an
+		// expired lock" or "lock: token mismatch") all refer to the token.
+		if token != "" && strings.Contains(respErr, "token") {
+			return "", &client.Error{Op: op, Client: f, Err: fmt.Errorf("%s: %w: %s", name, ErrLockExpired, respErr)}
+		}
+		return "", &client.Error{Op: op, Client: f, Err: errors.New(respErr)}
+	}
+	return resp.GetRenewToken(), nil
+}
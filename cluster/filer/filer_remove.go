@@ -13,19 +13,24 @@ import (
 	"google.golang.org/grpc/status"
 )
 
-// Remove ...
+// Remove removes the named entry.
+//
+// Data for the entry is only deleted if it is not shared with other hard links.
 func (f *Filer) Remove(ctx context.Context, name string) (*Entry, error) {
 	e, err := f.Stat(ctx, name)
 	if err != nil {
 		return e, err
 	}
 
-	log.Trace("[filer] remove", log.String("name", name), log.String("path", e.Path().String()))
+	log.Trace("[filer] remove",
+		log.String("name", name),
+		log.String("path", e.Path().String()),
+		log.Int("links", e.Links()))
 
 	req := &filer_pb.DeleteEntryRequest{
 		Directory:          e.Path().Dir(),
 		Name:               e.Path().Name(),
-		IsDeleteData:       true,
+		IsDeleteData:       e.Links() <= 1,
 		IsFromOtherCluster: false,
 		Signatures:         []int32{f.signature},
 	}
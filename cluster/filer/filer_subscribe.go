@@ -0,0 +1,71 @@
+package filer
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/transientvariable/lettuce/client"
+	"github.com/transientvariable/lettuce/pb/filer_pb"
+	"github.com/transientvariable/log-go"
+
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// subscriberName defines the client name reported to the filer when subscribing to metadata events.
+	subscriberName = "lettuce"
+)
+
+// Subscribe calls fn for each metadata event for entries with a path that starts with one of the provided prefixes,
+// where each prefix is a path relative to the root of the Filer. Only events that occurred after sinceNs, which is
+// the number of nanoseconds since the Unix epoch, are received.
+//
+// Subscribe blocks until the context is done, the subscription fails, or fn returns an error, which is returned by
+// Subscribe as is.
+func (f *Filer) Subscribe(ctx context.Context,
+	sinceNs int64,
+	fn func(*filer_pb.SubscribeMetadataResponse) error,
+	prefixes ...string,
+) error {
+	paths := make([]string, len(prefixes))
+	for i, p := range prefixes {
+		pp, err := f.path(p)
+		if err != nil {
+			return &client.Error{Op: "subscribe", Client: f, Err: err}
+		}
+		paths[i] = pp.String()
+	}
+
+	log.Trace("[filer] subscribe", log.Any("prefixes", paths), log.Int64("since_ns", sinceNs))
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := f.PB().SubscribeMetadata(ctx, &filer_pb.SubscribeMetadataRequest{
+		ClientName:   subscriberName,
+		PathPrefixes: paths,
+		SinceNs:      sinceNs,
+	})
+	if err != nil {
+		return &client.Error{Op: "subscribe", Client: f, Err: err}
+	}
+
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+
+			if s, ok := status.FromError(err); ok {
+				return &client.Error{Op: "subscribe", Client: f, Err: errors.New(s.Message())}
+			}
+			return &client.Error{Op: "subscribe", Client: f, Err: err}
+		}
+
+		if err := fn(resp); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package filer
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/transientvariable/lettuce/client"
+	"github.com/transientvariable/lettuce/pb/filer_pb"
+	"github.com/transientvariable/log-go"
+
+	gofs "io/fs"
+)
+
+// Symlink creates a new Filer entry with the provided name that represents a symbolic link to target.
+//
+// The target is stored as provided and is not required to exist. If the operation is successful, an Entry will be
+// returned representing the symbolic link.
+func (f *Filer) Symlink(ctx context.Context, target string, name string) (*Entry, error) {
+	if target = strings.TrimSpace(target); target == "" {
+		return nil, &client.Error{Op: "symlink", Client: f, Err: errors.New("target is required for symlink")}
+	}
+
+	e, err := f.Stat(ctx, name)
+	if err != nil {
+		if !errors.Is(err, gofs.ErrNotExist) {
+			return nil, err
+		}
+	}
+
+	if e != nil {
+		return e, &client.Error{Op: "symlink", Client: f, Err: gofs.ErrExist}
+	}
+
+	path, err := f.path(name)
+	if err != nil {
+		return nil, &client.Error{Op: "symlink", Client: f, Err: err}
+	}
+
+	log.Trace("[filer] symlink",
+		log.String("name", name),
+		log.String("path", path.String()),
+		log.String("target", target))
+
+	pbEntry := &filer_pb.Entry{
+		Name: path.Name(),
+		Attributes: &filer_pb.FuseAttributes{
+			Mtime:         time.Now().Unix(),
+			Crtime:        time.Now().Unix(),
+			FileMode:      uint32(gofs.ModeSymlink | 0777),
+			Gid:           uint32(f.root.entry.GID()),
+			Uid:           uint32(f.root.entry.UID()),
+			SymlinkTarget: target,
+		},
+	}
+
+	if err := f.createEntry(ctx, "symlink", path, pbEntry); err != nil {
+		return nil, err
+	}
+	return f.NewEntry(filepath.Dir(name), pbEntry)
+}
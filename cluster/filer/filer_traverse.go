@@ -0,0 +1,83 @@
+package filer
+
+import (
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/transientvariable/lettuce/client"
+	"github.com/transientvariable/lettuce/pb/filer_pb"
+	"github.com/transientvariable/log-go"
+
+	"google.golang.org/grpc/status"
+)
+
+// Traverse calls fn for each entry in the subtree of the named directory in breadth-first order using a single
+// request. The named directory is not included. Entries with one of the excluded paths are skipped along with their
+// descendants, where each path is relative to the root of the Filer. Excluded paths match complete path elements, so
+// excluding "cache" does not skip "cache2".
+//
+// Traversal stops if fn returns an error, which is returned by Traverse as is.
+func (f *Filer) Traverse(ctx context.Context, name string, fn func(*Entry) error, excluded ...string) error {
+	p, err := f.path(name)
+	if err != nil {
+		return &client.Error{Op: "traverse", Client: f, Err: err}
+	}
+
+	// The filer matches excluded prefixes as strings, so it is only sent the prefixes of the descendants of each
+	// excluded path, and the excluded paths themselves are skipped when received.
+	paths := make([]string, len(excluded))
+	prefixes := make([]string, len(excluded))
+	for i, e := range excluded {
+		ep, err := f.path(e)
+		if err != nil {
+			return &client.Error{Op: "traverse", Client: f, Err: err}
+		}
+		paths[i] = ep.String()
+		prefixes[i] = strings.TrimSuffix(ep.String(), "/") + "/"
+	}
+
+	log.Trace("[filer] traverse", log.String("path", p.String()), log.Any("excluded_prefixes", prefixes))
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := f.PB().TraverseBfsMetadata(ctx, &filer_pb.TraverseBfsMetadataRequest{
+		Directory:        p.String(),
+		ExcludedPrefixes: prefixes,
+	})
+	if err != nil {
+		return &client.Error{Op: "traverse", Client: f, Err: err}
+	}
+
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+
+			if s, ok := status.FromError(err); ok {
+				return &client.Error{Op: "traverse", Client: f, Err: errors.New(s.Message())}
+			}
+			return &client.Error{Op: "traverse", Client: f, Err: err}
+		}
+
+		e, err := f.NewEntry(resp.GetDirectory(), resp.GetEntry())
+		if err != nil {
+			return err
+		}
+
+		if ep := e.Path().String(); slices.Contains(paths, ep) || slices.ContainsFunc(prefixes, func(prefix string) bool {
+			return strings.HasPrefix(ep, prefix)
+		}) {
+			continue
+		}
+
+		if err := fn(e); err != nil {
+			return err
+		}
+	}
+}
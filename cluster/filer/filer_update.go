@@ -4,7 +4,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"time"
 
 	"github.com/transientvariable/anchor"
 	"github.com/transientvariable/lettuce/client"
@@ -14,7 +13,10 @@ import (
 	"google.golang.org/grpc/status"
 )
 
-// Update ...
+// Update persists the metadata for the provided Entry.
+//
+// The attributes of the Entry are written as-is, so callers modifying the content of an Entry are responsible for
+// updating the modification time (see Entry.SetModTime).
 func (f *Filer) Update(ctx context.Context, entry *Entry) error {
 	if entry == nil {
 		return &client.Error{Op: "update", Client: f, Err: errors.New("entry is required for update")}
@@ -34,7 +36,6 @@ func (f *Filer) Update(ctx context.Context, entry *Entry) error {
 	if fe.GetAttributes() == nil {
 		return &client.Error{Op: "update", Client: f, Err: errors.New("protobuf entry attributes missing")}
 	}
-	fe.GetAttributes().Mtime = time.Now().Unix()
 
 	req := &filer_pb.UpdateEntryRequest{
 		Directory:  entry.Path().Dir(),
@@ -12,15 +12,20 @@ import (
 
 // AssignVolume assigns a portion of file content (chunk) represented by the provided path to a volume server and
 // returns the file ID and url.URL which can be used for writing data.
-func (f *Filer) AssignVolume(ctx context.Context, path string) (string, url.URL, error) {
+//
+// The provided options define the Placement for the chunk (e.g. WithCollection, WithReplication).
+func (f *Filer) AssignVolume(ctx context.Context, path string, options ...func(*Placement)) (string, url.URL, error) {
 	if path = strings.TrimSpace(path); path == "" {
 		return "", url.URL{}, &client.Error{Op: "assign", Client: f, Err: errors.New("path is required for assigning volume")}
 	}
 
-	resp, err := f.PB().AssignVolume(ctx, &filer_pb.AssignVolumeRequest{
+	req := &filer_pb.AssignVolumeRequest{
 		Count: 1,
 		Path:  path,
-	})
+	}
+	newPlacement(options...).assign(req)
+
+	resp, err := f.PB().AssignVolume(ctx, req)
 	if err != nil {
 		return "", url.URL{}, &client.Error{Op: "assign", Client: f, Err: err}
 	}
@@ -0,0 +1,152 @@
+package filer
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/transientvariable/log-go"
+)
+
+// locker defines the operations used by a Lock for renewing and releasing a distributed lock, which are implemented by
+// Filer.
+type locker interface {
+	TryRenew(ctx context.Context, name string, ttl time.Duration, owner string, token string) (string, error)
+	Unlock(ctx context.Context, name string, token string) error
+}
+
+// Lock is a distributed lock held through a Filer, which is renewed in the background until it is released.
+type Lock struct {
+	cancel   context.CancelFunc
+	done     chan struct{}
+	expires  time.Time
+	locker   locker
+	lost     chan error
+	mutex    sync.Mutex
+	name     string
+	owner    string
+	released bool
+	token    string
+	ttl      time.Duration
+}
+
+// newLock returns a Lock for the lock with the provided name and renewal token, which was acquired by a request sent at
+// the time acquired.
+func newLock(l locker, name string, ttl time.Duration, owner string, token string, acquired time.Time) *Lock {
+	ctx, cancel := context.WithCancel(context.Background())
+	lock := &Lock{
+		cancel:  cancel,
+		done:    make(chan struct{}),
+		expires: acquired.Add(ttl),
+		locker:  l,
+		lost:    make(chan error, 1),
+		name:    name,
+		owner:   owner,
+		token:   token,
+		ttl:     ttl,
+	}
+	go lock.renew(ctx)
+	return lock
+}
+
+// Lost returns a channel that receives the cause if ownership of the Lock is lost. The channel is closed once the Lock
+// is no longer renewed.
+//
+// Ownership is reported as lost once the Lock can no longer be renewed while at least a third of its time-to-live
+// remains, so that the owner can stop acting on the Lock before it expires and another owner can acquire it. The clock
+// of the filer may differ from the local clock, so the margin should exceed the expected clock skew.
+func (l *Lock) Lost() <-chan error {
+	return l.lost
+}
+
+// Name returns the name of the Lock.
+func (l *Lock) Name() string {
+	return l.name
+}
+
+// Owner returns the owner of the Lock.
+func (l *Lock) Owner() string {
+	return l.owner
+}
+
+// Release stops renewing the Lock and releases it. Calling Release more than once has no effect.
+func (l *Lock) Release(ctx context.Context) error {
+	l.mutex.Lock()
+	if l.released {
+		l.mutex.Unlock()
+		return nil
+	}
+	l.released = true
+	l.mutex.Unlock()
+
+	l.cancel()
+	<-l.done
+
+	log.Trace("[filer] releasing lock", log.String("name", l.name), log.String("owner", l.owner))
+
+	// The renewal token is cleared once ownership is lost, in which case there is nothing to release.
+	if token := l.renewToken(); token != "" {
+		return l.locker.Unlock(ctx, l.name, token)
+	}
+	return nil
+}
+
+// renew renews the Lock every third of its time-to-live until ctx is done. Failed renewals are retried until less than
+// a third of the time-to-live remains, or the filer reports that the Lock is held by another owner or has expired.
+func (l *Lock) renew(ctx context.Context) {
+	defer close(l.done)
+	defer close(l.lost)
+
+	margin := l.ttl / 3
+	timer := time.NewTimer(margin)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		// Each attempt is bounded by the time at which ownership is reported as lost, so a stuck connection does not
+		// delay reporting the loss.
+		deadline := l.expires.Add(-margin)
+		sent := time.Now()
+		rctx, cancel := context.WithDeadline(ctx, deadline)
+		token, err := l.locker.TryRenew(rctx, l.name, l.ttl, l.owner, l.renewToken())
+		cancel()
+
+		if err == nil {
+			l.setRenewToken(token)
+			l.expires = sent.Add(l.ttl)
+			timer.Reset(margin)
+			continue
+		}
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		log.Warn("[filer] could not renew lock", log.String("name", l.name), log.Err(err))
+
+		if errors.Is(err, ErrLocked) || errors.Is(err, ErrLockExpired) || !time.Now().Before(deadline) {
+			l.setRenewToken("")
+			l.lost <- errors.Join(ErrLockLost, err)
+			return
+		}
+		timer.Reset(min(lockRetryInterval, l.ttl/10, time.Until(deadline)))
+	}
+}
+
+func (l *Lock) renewToken() string {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.token
+}
+
+func (l *Lock) setRenewToken(token string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.token = token
+}
@@ -0,0 +1,144 @@
+package filer
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testLockTTL = 300 * time.Millisecond
+
+// testLocker is a locker that renews locks using renew, and records the renewal tokens of released locks.
+type testLocker struct {
+	mutex    sync.Mutex
+	renew    func(ctx context.Context, n int) error
+	renewals int
+	unlocked []string
+}
+
+func (l *testLocker) TryRenew(ctx context.Context, _ string, _ time.Duration, _ string, _ string) (string, error) {
+	l.mutex.Lock()
+	l.renewals++
+	n := l.renewals
+	l.mutex.Unlock()
+
+	if l.renew != nil {
+		if err := l.renew(ctx, n); err != nil {
+			return "", err
+		}
+	}
+	return "token-" + strconv.Itoa(n), nil
+}
+
+func (l *testLocker) Unlock(_ context.Context, _ string, token string) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.unlocked = append(l.unlocked, token)
+	return nil
+}
+
+func (l *testLocker) released() []string {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.unlocked
+}
+
+func TestLockRenew(t *testing.T) {
+	l := &testLocker{}
+	lock := newLock(l, "test", testLockTTL, "owner", "token-0", time.Now())
+
+	select {
+	case err := <-lock.Lost():
+		t.Fatalf("lock lost: %v", err)
+	case <-time.After(3 * testLockTTL):
+	}
+
+	require.NoError(t, lock.Release(context.Background()))
+	require.Len(t, l.released(), 1)
+	assert.NotEqual(t, "token-0", l.released()[0])
+
+	require.NoError(t, lock.Release(context.Background()))
+	assert.Len(t, l.released(), 1)
+
+	_, ok := <-lock.Lost()
+	assert.False(t, ok)
+}
+
+func TestLockLost(t *testing.T) {
+	tests := []struct {
+		name  string
+		renew func(ctx context.Context, n int) error
+		err   error
+	}{
+		{
+			name:  "held by another owner",
+			renew: func(context.Context, int) error { return ErrLocked },
+			err:   ErrLocked,
+		},
+		{
+			name:  "expired",
+			renew: func(context.Context, int) error { return ErrLockExpired },
+			err:   ErrLockExpired,
+		},
+		{
+			name: "renewal blocked",
+			renew: func(ctx context.Context, _ int) error {
+				<-ctx.Done()
+				return ctx.Err()
+			},
+			err: context.DeadlineExceeded,
+		},
+		{
+			name:  "renewal failed",
+			renew: func(context.Context, int) error { return assert.AnError },
+			err:   assert.AnError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := &testLocker{renew: tt.renew}
+			acquired := time.Now()
+			lock := newLock(l, "test", testLockTTL, "owner", "token-0", acquired)
+
+			select {
+			case err := <-lock.Lost():
+				assert.ErrorIs(t, err, ErrLockLost)
+				assert.ErrorIs(t, err, tt.err)
+				assert.Less(t, time.Since(acquired), testLockTTL-testLockTTL/3+testLockTTL/10)
+			case <-time.After(2 * testLockTTL):
+				t.Fatal("lock not lost")
+			}
+
+			require.NoError(t, lock.Release(context.Background()))
+			assert.Empty(t, l.released())
+		})
+	}
+}
+
+func TestLockRenewRetry(t *testing.T) {
+	l := &testLocker{
+		renew: func(_ context.Context, n int) error {
+			if n == 1 {
+				return assert.AnError
+			}
+			return nil
+		},
+	}
+	lock := newLock(l, "test", testLockTTL, "owner", "token-0", time.Now())
+
+	select {
+	case err := <-lock.Lost():
+		t.Fatalf("lock lost: %v", err)
+	case <-time.After(2 * testLockTTL):
+	}
+
+	require.NoError(t, lock.Release(context.Background()))
+	require.Len(t, l.released(), 1)
+	assert.NotEqual(t, "token-0", l.released()[0])
+}
@@ -0,0 +1,101 @@
+package filer
+
+import (
+	"math"
+	"time"
+
+	"github.com/transientvariable/lettuce/pb/filer_pb"
+)
+
+// Placement defines properties for how the content of an entry is stored by volume servers.
+type Placement struct {
+	collection  string
+	dataCenter  string
+	dataNode    string
+	diskType    string
+	rack        string
+	replication string
+	ttl         time.Duration
+}
+
+// newPlacement creates a Placement using the provided options.
+func newPlacement(options ...func(*Placement)) Placement {
+	var p Placement
+	for _, opt := range options {
+		opt(&p)
+	}
+	return p
+}
+
+// assign sets the properties for the Placement on the provided filer_pb.AssignVolumeRequest.
+func (p Placement) assign(req *filer_pb.AssignVolumeRequest) {
+	req.Collection = p.collection
+	req.DataCenter = p.dataCenter
+	req.DataNode = p.dataNode
+	req.DiskType = p.diskType
+	req.Rack = p.rack
+	req.Replication = p.replication
+	req.TtlSec = p.ttlSec()
+}
+
+// ttlSec returns the time-to-live for the Placement in seconds, rounded up to the nearest second. Time-to-live values
+// that do not fit in the request are clamped to the largest value that does.
+func (p Placement) ttlSec() int32 {
+	if p.ttl <= 0 {
+		return 0
+	}
+	return int32(min((p.ttl-1)/time.Second+1, math.MaxInt32))
+}
+
+// WithCollection sets the collection for the volumes that content is assigned to.
+func WithCollection(collection string) func(*Placement) {
+	return func(p *Placement) {
+		p.collection = collection
+	}
+}
+
+// WithDataCenter sets the data center for the volume servers that content is assigned to.
+func WithDataCenter(dataCenter string) func(*Placement) {
+	return func(p *Placement) {
+		p.dataCenter = dataCenter
+	}
+}
+
+// WithDataNode sets the volume server that content is assigned to.
+func WithDataNode(dataNode string) func(*Placement) {
+	return func(p *Placement) {
+		p.dataNode = dataNode
+	}
+}
+
+// WithDiskType sets the disk type (e.g. hdd, ssd) for the volumes that content is assigned to.
+func WithDiskType(diskType string) func(*Placement) {
+	return func(p *Placement) {
+		p.diskType = diskType
+	}
+}
+
+// WithRack sets the rack for the volume servers that content is assigned to.
+func WithRack(rack string) func(*Placement) {
+	return func(p *Placement) {
+		p.rack = rack
+	}
+}
+
+// WithReplication sets the replication for the volumes that content is assigned to using the SeaweedFS replica
+// placement format (e.g. 000, 010).
+func WithReplication(replication string) func(*Placement) {
+	return func(p *Placement) {
+		p.replication = replication
+	}
+}
+
+// WithTTL sets the time-to-live for an entry and its content, after which both are removed by the cluster. Content is
+// assigned to volumes created with the same time-to-live, which are reclaimed by the cluster once they expire.
+//
+// A time-to-live less than or equal to zero means that the entry does not expire.
+func WithTTL(ttl time.Duration) func(*Placement) {
+	return func(p *Placement) {
+		p.ttl = ttl
+	}
+}
@@ -0,0 +1,83 @@
+package filer
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/transientvariable/lettuce/pb/filer_pb"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestPlacementTTLSec(t *testing.T) {
+	tests := []struct {
+		name string
+		ttl  time.Duration
+		want int32
+	}{
+		{name: "none"},
+		{name: "negative", ttl: -time.Second},
+		{name: "nanosecond", ttl: time.Nanosecond, want: 1},
+		{name: "second", ttl: time.Second, want: 1},
+		{name: "rounded up", ttl: time.Second + time.Millisecond, want: 2},
+		{name: "just under", ttl: 2*time.Second - time.Nanosecond, want: 2},
+		{name: "day", ttl: 24 * time.Hour, want: 86400},
+		{name: "largest", ttl: math.MaxInt32 * time.Second, want: math.MaxInt32},
+		{name: "clamped", ttl: (math.MaxInt32 + 1) * time.Second, want: math.MaxInt32},
+		{name: "maximum duration", ttl: math.MaxInt64, want: math.MaxInt32},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, newPlacement(WithTTL(tt.ttl)).ttlSec())
+		})
+	}
+}
+
+func TestPlacementAssign(t *testing.T) {
+	tests := []struct {
+		name    string
+		options []func(*Placement)
+		want    *filer_pb.AssignVolumeRequest
+	}{
+		{name: "default", want: &filer_pb.AssignVolumeRequest{}},
+		{
+			name: "all options",
+			options: []func(*Placement){
+				WithCollection("logs"),
+				WithDataCenter("dc1"),
+				WithDataNode("10.0.0.1:8080"),
+				WithDiskType("ssd"),
+				WithRack("rack1"),
+				WithReplication("010"),
+				WithTTL(time.Hour),
+			},
+			want: &filer_pb.AssignVolumeRequest{
+				Collection:  "logs",
+				DataCenter:  "dc1",
+				DataNode:    "10.0.0.1:8080",
+				DiskType:    "ssd",
+				Rack:        "rack1",
+				Replication: "010",
+				TtlSec:      3600,
+			},
+		},
+		{
+			name:    "later options take precedence",
+			options: []func(*Placement){WithCollection("logs"), WithCollection("metrics")},
+			want:    &filer_pb.AssignVolumeRequest{Collection: "metrics"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &filer_pb.AssignVolumeRequest{Path: "/test/file"}
+			newPlacement(tt.options...).assign(req)
+
+			tt.want.Path = "/test/file"
+			assert.True(t, proto.Equal(tt.want, req), req)
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package lettuce
+
+import (
+	"mime"
+	"path"
+	"strings"
+
+	"github.com/transientvariable/lettuce/chunk"
+)
+
+// compressionPolicy determines the chunk.Compression used for writing file content.
+type compressionPolicy struct {
+	compression chunk.Compression
+	mimeTypes   []string
+}
+
+// forName returns the chunk.Compression for the named file based on the MIME type for its extension.
+//
+// If the policy does not define any MIME types, the content for all files is compressed. MIME types ending with `/*`
+// match all subtypes (e.g. text/* matches text/plain and text/csv).
+func (p compressionPolicy) forName(name string) chunk.Compression {
+	if p.compression == chunk.CompressionNone || len(p.mimeTypes) == 0 {
+		return p.compression
+	}
+
+	mt, _, err := mime.ParseMediaType(mime.TypeByExtension(path.Ext(name)))
+	if err != nil {
+		return chunk.CompressionNone
+	}
+
+	for _, t := range p.mimeTypes {
+		if prefix, ok := strings.CutSuffix(t, "*"); ok && strings.HasPrefix(mt, prefix) || t == mt {
+			return p.compression
+		}
+	}
+	return chunk.CompressionNone
+}
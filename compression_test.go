@@ -0,0 +1,82 @@
+package lettuce
+
+import (
+	"testing"
+
+	"github.com/transientvariable/lettuce/chunk"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompressionPolicyForName(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy compressionPolicy
+		file   string
+		want   chunk.Compression
+	}{
+		{name: "none", policy: compressionPolicy{mimeTypes: []string{"text/html"}}, file: "a.html"},
+		{
+			name:   "all files",
+			policy: compressionPolicy{compression: chunk.CompressionGzip},
+			file:   "a.png",
+			want:   chunk.CompressionGzip,
+		},
+		{
+			name:   "all files without extension",
+			policy: compressionPolicy{compression: chunk.CompressionZstd},
+			file:   "a",
+			want:   chunk.CompressionZstd,
+		},
+		{
+			name:   "exact type",
+			policy: compressionPolicy{compression: chunk.CompressionGzip, mimeTypes: []string{"application/json"}},
+			file:   "dir/a.json",
+			want:   chunk.CompressionGzip,
+		},
+		{
+			name:   "exact type with parameters",
+			policy: compressionPolicy{compression: chunk.CompressionGzip, mimeTypes: []string{"text/html"}},
+			file:   "a.html",
+			want:   chunk.CompressionGzip,
+		},
+		{
+			name:   "wildcard subtype",
+			policy: compressionPolicy{compression: chunk.CompressionGzip, mimeTypes: []string{"text/*"}},
+			file:   "a.css",
+			want:   chunk.CompressionGzip,
+		},
+		{
+			name:   "wildcard subtype mismatch",
+			policy: compressionPolicy{compression: chunk.CompressionGzip, mimeTypes: []string{"text/*"}},
+			file:   "a.png",
+		},
+		{
+			name:   "type mismatch",
+			policy: compressionPolicy{compression: chunk.CompressionGzip, mimeTypes: []string{"image/svg+xml"}},
+			file:   "a.png",
+		},
+		{
+			name:   "multiple types",
+			policy: compressionPolicy{compression: chunk.CompressionGzip, mimeTypes: []string{"text/*", "image/svg+xml"}},
+			file:   "a.svg",
+			want:   chunk.CompressionGzip,
+		},
+		{
+			name:   "unknown extension",
+			policy: compressionPolicy{compression: chunk.CompressionGzip, mimeTypes: []string{"text/*"}},
+			file:   "a.unknown-extension",
+		},
+		{
+			name:   "no extension",
+			policy: compressionPolicy{compression: chunk.CompressionGzip, mimeTypes: []string{"text/*"}},
+			file:   "a",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.policy.forName(tt.file))
+		})
+	}
+}
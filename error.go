@@ -0,0 +1,16 @@
+package lettuce
+
+// Enumeration of errors that may be returned by Lettuce operations.
+const (
+	ErrNoXattr       = lettuceError("extended attribute not found")
+	ErrSymlinkLoop   = lettuceError("too many levels of symbolic links")
+	ErrXattrTooLarge = lettuceError("extended attribute name or value too large")
+)
+
+// lettuceError defines the type for errors that may be returned by Lettuce operations.
+type lettuceError string
+
+// Error returns the cause of a Lettuce operation error.
+func (e lettuceError) Error() string {
+	return string(e)
+}
@@ -5,11 +5,15 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net/url"
+	"slices"
 	"sync"
+	"time"
 
 	"github.com/transientvariable/fs-go"
 	"github.com/transientvariable/lettuce/chunk"
 	"github.com/transientvariable/lettuce/cluster/filer"
+	"github.com/transientvariable/lettuce/pb/filer_pb"
 	"github.com/transientvariable/lettuce/support"
 	"github.com/transientvariable/log-go"
 
@@ -20,6 +24,7 @@ import (
 var (
 	_ fs.File     = (*File)(nil)
 	_ gohttp.File = (*File)(nil)
+	_ io.WriterAt = (*File)(nil)
 )
 
 // File provides access to a single file or directory.
@@ -28,19 +33,25 @@ var (
 type File struct {
 	client    *gohttp.Client
 	closed    bool
+	compress  *chunk.Compression
 	ctx       context.Context
 	ctxCancel context.CancelFunc
 	ctxParent context.Context
+	deferred  bool
 	dirIter   fs.DirIterator
 	entry     *filer.Entry
 	fileInfo  gofs.FileInfo
 	flag      int
 	let       *Lettuce
+	modified  bool
 	mutex     sync.Mutex
+	pending   []*filer_pb.FileChunk
+	placement []func(*filer.Placement)
 	reader    io.ReadSeekCloser
 	rOff      int64
+	ttl       *time.Duration
 	wOff      int64
-	writer    io.WriteCloser
+	writer    *chunk.Writer
 
 	blue error
 }
@@ -51,62 +62,101 @@ func newFile(let *Lettuce, flag int, options ...func(*File)) (*File, error) {
 		opt(f)
 	}
 
+	if err := f.init(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// init prepares the File for reading once the options for the File have been applied.
+func (f *File) init() error {
 	if f.ctxParent == nil {
 		f.ctxParent = context.Background()
 	}
 	f.ctx, f.ctxCancel = context.WithCancel(f.ctxParent)
 
 	if f.entry == nil {
-		f.entry = let.entry
-		return f, nil
+		f.entry = f.let.entry
+		return nil
 	}
 
-	if !f.entry.IsDir() && flag&fs.O_TRUNC > 0 {
+	if !f.entry.IsDir() && f.flag&fs.O_TRUNC > 0 {
 		log.Trace(fmt.Sprintf("[lettuce:file] truncating file ref: \n%s", f.entry))
 
-		path := fsPath(let, f.entry.Path())
-		if _, err := let.cluster.Truncate(f.ctx, path); err != nil {
-			return nil, err
+		path := fsPath(f.let, f.entry.Path())
+		if _, err := f.let.cluster.Truncate(f.ctx, path, 0); err != nil {
+			return err
 		}
 
-		e, err := let.cluster.Filer().Stat(f.ctx, path)
+		e, err := f.let.cluster.Filer().Stat(f.ctx, path)
 		if err != nil {
-			return nil, err
+			return err
 		}
 		f.entry = e
 	}
 
 	fi, err := f.Stat()
 	if err != nil {
-		return nil, err
+		return err
 	}
 	f.fileInfo = fi
 
 	if f.client == nil {
-		f.client = let.httpClient
+		f.client = f.let.httpClient
 	}
 
-	if err := f.checkRead("newFile"); err == nil {
+	if err := f.checkRead("newFile"); err == nil && len(f.entry.Content()) > 0 {
+		f.reader = &contentReader{SectionReader: io.NewSectionReader(content(f.entry.Content()), 0, f.entry.Size())}
+	} else if err == nil {
 		f.reader, err = chunk.NewReader(
-			let.cluster.Master().FindVolumes,
+			f.let.cluster.Master().FindVolumes,
 			f.entry.Chunks(),
 			chunk.WithReaderContext(f.ctx))
 		if err != nil {
-			return nil, err
+			return err
 		}
 	}
+	return nil
+}
 
-	if err := f.checkWrite("newFile"); err == nil {
-		f.writer, err = chunk.NewWriter(
-			f.entry.Path().String(),
-			let.cluster.Filer().AssignVolume,
-			chunk.WithWriterChunks(f.entry.Chunks()),
-			chunk.WithWriterContext(f.ctx))
-		if err != nil {
-			return nil, err
-		}
+// Chmod changes the mode of the File to mode.
+func (f *File) Chmod(mode gofs.FileMode) error {
+	return f.chattr("chmod", func(e *filer.Entry) error { return chmod(e, mode) })
+}
+
+// Chown changes the numeric uid and gid of the File.
+func (f *File) Chown(uid int, gid int) error {
+	return f.chattr("chown", func(e *filer.Entry) error { return chown(e, uid, gid) })
+}
+
+// Chtimes changes the modification time of the File. See Lettuce.Chtimes for details.
+func (f *File) Chtimes(atime time.Time, mtime time.Time) error {
+	return f.chattr("chtimes", func(e *filer.Entry) error { return chtimes(e, mtime) })
+}
+
+// Getxattr returns the value of the extended attribute attr for the File. See Lettuce.Getxattr for details.
+func (f *File) Getxattr(attr string) ([]byte, error) {
+	if err := f.checkOpen("getxattr"); err != nil {
+		return nil, err
 	}
-	return f, nil
+
+	v, err := getxattr(f.entry, attr)
+	if err != nil {
+		return nil, fmt.Errorf("lettuce_file: %w", &gofs.PathError{
+			Op:   "getxattr",
+			Path: f.fileInfo.Name(),
+			Err:  err,
+		})
+	}
+	return v, nil
+}
+
+// Listxattr returns the sorted names of the extended attributes for the File.
+func (f *File) Listxattr() ([]string, error) {
+	if err := f.checkOpen("listxattr"); err != nil {
+		return nil, err
+	}
+	return listxattr(f.entry), nil
 }
 
 func (f *File) Close() error {
@@ -217,7 +267,20 @@ func (f *File) ReadFrom(r io.Reader) (int64, error) {
 	buf := support.AcquireBufferN(bufSize)
 	defer support.ReleaseBuffer(buf)
 
-	n, err := io.CopyBuffer(f.writer, r, buf)
+	if err := f.seekWriter(f.wOff); err != nil {
+		return 0, fmt.Errorf("lettuce_file: %w", &gofs.PathError{
+			Op:   "readFrom",
+			Path: f.fileInfo.Name(),
+			Err:  err,
+		})
+	}
+
+	n, err := io.CopyBuffer(&sniffWriter{f: f, off: f.wOff}, r, buf)
+	f.wOff += n
+	if n > 0 {
+		f.modify()
+	}
+
 	if err != nil {
 		return n, fmt.Errorf("lettuce_file: %w", &gofs.PathError{
 			Op:   "readFrom",
@@ -225,7 +288,6 @@ func (f *File) ReadFrom(r io.Reader) (int64, error) {
 			Err:  err,
 		})
 	}
-	f.wOff += n
 	return n, nil
 }
 
@@ -247,15 +309,30 @@ func (f *File) ReadDir(n int) ([]gofs.DirEntry, error) {
 	return entries, err
 }
 
+// Removexattr removes the extended attribute attr from the File.
+func (f *File) Removexattr(attr string) error {
+	return f.chattr("removexattr", func(e *filer.Entry) error { return removexattr(e, attr) })
+}
+
+// Setxattr sets the value of the extended attribute attr for the File. See Lettuce.Setxattr for details.
+func (f *File) Setxattr(attr string, data []byte, flags int) error {
+	return f.chattr("setxattr", func(e *filer.Entry) error { return setxattr(e, attr, data, flags) })
+}
+
+// Seek sets the offset for the next Read or Write on the File to offset, interpreted according to whence:
+// io.SeekStart means relative to the start of the file, io.SeekCurrent means relative to the current offset, and
+// io.SeekEnd means relative to the end.
+//
+// Writes to a File opened with O_APPEND always occur at the end of the file, regardless of the offset.
 func (f *File) Seek(off int64, whence int) (int64, error) {
-	if err := f.checkRead("seek"); err != nil {
+	if err := f.checkRegularFile("seek"); err != nil {
 		return 0, err
 	}
 
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
-	s, err := f.reader.Seek(off, whence)
+	s, err := f.seek(off, whence)
 	if err != nil {
 		return 0, fmt.Errorf("lettuce_file: %w", &gofs.PathError{
 			Op:   "seek",
@@ -263,10 +340,11 @@ func (f *File) Seek(off int64, whence int) (int64, error) {
 			Err:  err,
 		})
 	}
-	f.rOff = s
 	return s, nil
 }
 
+// Stat returns a fs.FileInfo describing the File. As for Lettuce.Stat, the fs.FileInfo reports the number of hard links
+// to the File (see Links), and its Sys method returns the *filer.Entry for the File.
 func (f *File) Stat() (gofs.FileInfo, error) {
 	if f == nil {
 		return nil, gofs.ErrInvalid
@@ -280,14 +358,53 @@ func (f *File) Stat() (gofs.FileInfo, error) {
 		})
 	}
 
-	e, err := FSEntry(f.let, f.entry)
+	e, err := newFileInfo(f.let, f.entry)
 	if err != nil {
 		return nil, err
 	}
 	return e, nil
 }
 
+// Sync commits the current contents of the File to storage.
+//
+// Content buffered for writing is written as a chunk, regardless of the chunk size, and the chunk list for the File is
+// persisted to the filer, so content written before a call to Sync is not lost if the process fails before the File
+// is closed.
 func (f *File) Sync() error {
+	if err := f.checkOpen("sync"); err != nil {
+		return err
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if err := f.sync(); err != nil {
+		return fmt.Errorf("lettuce_file: %w", &gofs.PathError{
+			Op:   "sync",
+			Path: f.fileInfo.Name(),
+			Err:  err,
+		})
+	}
+	return nil
+}
+
+// Truncate changes the size of the File. It does not change the offset used for reading or writing. See
+// Lettuce.Truncate for details.
+func (f *File) Truncate(size int64) error {
+	if err := f.checkWrite("truncate"); err != nil {
+		return err
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if err := f.truncate(size); err != nil {
+		return fmt.Errorf("lettuce_file: %w", &gofs.PathError{
+			Op:   "truncate",
+			Path: f.fileInfo.Name(),
+			Err:  err,
+		})
+	}
 	return nil
 }
 
@@ -303,6 +420,14 @@ func (f *File) Write(b []byte) (int, error) {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
+	if err := f.seekWriter(f.wOff); err != nil {
+		return 0, fmt.Errorf("lettuce_file: %w", &gofs.PathError{
+			Op:   "write",
+			Path: f.fileInfo.Name(),
+			Err:  err,
+		})
+	}
+
 	n, err := f.writer.Write(b)
 	if err != nil {
 		return n, fmt.Errorf("lettuce_file: %w", &gofs.PathError{
@@ -311,7 +436,62 @@ func (f *File) Write(b []byte) (int, error) {
 			Err:  err,
 		})
 	}
+	f.sniff(f.wOff, b[:n])
 	f.wOff += int64(n)
+	f.modify()
+	return n, nil
+}
+
+// WriteAt writes len(b) bytes to the File starting at byte offset off, without changing the offset used by Write.
+//
+// Content written over existing content replaces it, which allows formats that update headers after the body has been
+// written to be stored directly.
+func (f *File) WriteAt(b []byte, off int64) (int, error) {
+	if err := f.checkWrite("writeAt"); err != nil {
+		return 0, err
+	}
+
+	if f.flag&fs.O_APPEND != 0 {
+		return 0, fmt.Errorf("lettuce_file: %w", &gofs.PathError{
+			Op:   "writeAt",
+			Path: f.fileInfo.Name(),
+			Err:  errors.New("invalid use of WriteAt on file opened with O_APPEND"),
+		})
+	}
+
+	if off < 0 {
+		return 0, fmt.Errorf("lettuce_file: %w", &gofs.PathError{
+			Op:   "writeAt",
+			Path: f.fileInfo.Name(),
+			Err:  errors.New("negative offset"),
+		})
+	}
+
+	if len(b) == 0 {
+		return 0, nil
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if err := f.seekWriter(off); err != nil {
+		return 0, fmt.Errorf("lettuce_file: %w", &gofs.PathError{
+			Op:   "writeAt",
+			Path: f.fileInfo.Name(),
+			Err:  err,
+		})
+	}
+
+	n, err := f.writer.Write(b)
+	if err != nil {
+		return n, fmt.Errorf("lettuce_file: %w", &gofs.PathError{
+			Op:   "writeAt",
+			Path: f.fileInfo.Name(),
+			Err:  err,
+		})
+	}
+	f.sniff(off, b[:n])
+	f.modify()
 	return n, nil
 }
 
@@ -320,6 +500,33 @@ func (f *File) String() string {
 	return ""
 }
 
+func (f *File) chattr(op string, fn func(*filer.Entry) error) error {
+	if f == nil {
+		return gofs.ErrInvalid
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if err := f.checkOpen(op); err != nil {
+		return err
+	}
+
+	err := fn(f.entry)
+	if err == nil {
+		err = f.let.cluster.Filer().Update(f.ctx, f.entry)
+	}
+
+	if err != nil {
+		return fmt.Errorf("lettuce_file: %w", &gofs.PathError{
+			Op:   op,
+			Path: f.fileInfo.Name(),
+			Err:  err,
+		})
+	}
+	return nil
+}
+
 func (f *File) checkRegularFile(op string) error {
 	if f.entry.IsDir() {
 		return fmt.Errorf("lettuce_file: %w", &gofs.PathError{
@@ -331,6 +538,21 @@ func (f *File) checkRegularFile(op string) error {
 	return nil
 }
 
+func (f *File) checkOpen(op string) error {
+	if f == nil {
+		return gofs.ErrInvalid
+	}
+
+	if f.closed {
+		return fmt.Errorf("lettuce_file: %w", &gofs.PathError{
+			Op:   op,
+			Path: f.fileInfo.Name(),
+			Err:  gofs.ErrClosed,
+		})
+	}
+	return nil
+}
+
 func (f *File) checkRead(op string) error {
 	if err := f.checkRegularFile(op); err != nil {
 		return err
@@ -369,13 +591,32 @@ func (f *File) finalize() error {
 
 	if f.writer != nil {
 		err = errors.Join(err, f.writer.Close())
-		if f.wOff > 0 {
+		if err == nil && len(f.pending) > 0 {
+			err = f.let.cluster.Filer().Append(f.ctx, f.entry.Path().String(), f.pending...)
+		}
+		// Content written in append mode is committed by the filer as each chunk is written, so updating the entry
+		// would discard chunks appended concurrently by other clients.
+		if f.modified && f.flag&fs.O_APPEND == 0 {
 			err = errors.Join(err, f.let.cluster.Filer().Update(f.ctx, f.entry))
 		}
 	}
 	return err
 }
 
+// modify records that the content of the File has been modified.
+func (f *File) modify() {
+	f.modified = true
+	f.entry.SetModTime(time.Now())
+}
+
+// sniff sets the MIME type for the File using content written at the start of the File if the MIME type is not known
+// (e.g. from the file name extension). The MIME type is not set in append mode, since the entry is not updated.
+func (f *File) sniff(off int64, b []byte) {
+	if off == 0 && len(b) > 0 && f.flag&fs.O_APPEND == 0 && f.entry.Mime() == "" {
+		f.entry.SetMime(gohttp.DetectContentType(b))
+	}
+}
+
 func (f *File) readDir(n int) ([]*fs.Entry, error) {
 	fi, err := f.Stat()
 	if err != nil {
@@ -406,3 +647,242 @@ func (f *File) readDir(n int) ([]*fs.Entry, error) {
 	}
 	return f.dirIter.NextN(n)
 }
+
+func (f *File) seek(off int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+		// no-ops
+	case io.SeekCurrent:
+		if f.reader != nil {
+			off += f.rOff
+		} else {
+			off += f.wOff
+		}
+	case io.SeekEnd:
+		// Content buffered by the writer is not reflected in the size of the entry until it is written as a chunk.
+		if f.writer != nil {
+			if err := f.writer.Flush(); err != nil {
+				return 0, err
+			}
+		}
+		off += f.entry.Size()
+	default:
+		return 0, fmt.Errorf("invalid whence: %d", whence)
+	}
+
+	if off < 0 {
+		return 0, errors.New("negative position")
+	}
+
+	if f.reader != nil {
+		s, err := f.reader.Seek(off, io.SeekStart)
+		if err != nil {
+			return 0, err
+		}
+		f.rOff = s
+	}
+	f.wOff = off
+	return off, nil
+}
+
+// deferAppend records chunks written in append mode, so that they are committed using a single append when the File is
+// closed (see Lettuce.AppendFile).
+func (f *File) deferAppend(_ context.Context, _ string, chunks ...*filer_pb.FileChunk) error {
+	f.pending = append(f.pending, chunks...)
+	return nil
+}
+
+// openWriter creates the writer for the File on the first write, so that opening a File for writing without writing
+// to it (e.g. to change its dead properties over WebDAV) does not move content stored inline to a chunk.
+func (f *File) openWriter() error {
+	if f.writer != nil {
+		return nil
+	}
+
+	if err := f.uninline(); err != nil {
+		return err
+	}
+
+	opts := f.writerOptions()
+	if f.flag&fs.O_APPEND != 0 {
+		// The filer keeps the MD5 digest of the previous content when chunks are appended, which is ignored by
+		// filer.Entry.ETag once the appended chunks are retrieved. The entry is not updated to remove the digest, since
+		// that would discard chunks appended concurrently by other clients.
+		f.entry.ClearMd5()
+
+		appendFn := f.let.cluster.Filer().Append
+		if f.deferred {
+			appendFn = f.deferAppend
+		}
+		opts = append(opts, chunk.WithWriterAppend(appendFn), chunk.WithWriterOffset(f.entry.Size()))
+	} else if f.let.inlineSize > 0 && f.entry.Size() == 0 {
+		opts = append(opts, chunk.WithWriterInline(f.let.inlineSize, func(b []byte) error {
+			f.entry.SetContent(b)
+			return nil
+		}))
+	}
+
+	w, err := chunk.NewWriter(f.entry.Path().String(), f.assignVolume, opts...)
+	if err != nil {
+		return err
+	}
+	f.writer = w
+	return nil
+}
+
+// seekWriter positions the writer at the provided offset unless the File was opened with O_APPEND, in which case
+// content is always written at the end of the file. The writer is created if it does not exist.
+func (f *File) seekWriter(off int64) error {
+	if err := f.openWriter(); err != nil {
+		return err
+	}
+
+	if f.flag&fs.O_APPEND != 0 || f.writer.Offset() == off {
+		return nil
+	}
+
+	if _, err := f.writer.Seek(off, io.SeekStart); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (f *File) sync() error {
+	if f.writer == nil {
+		return nil
+	}
+
+	if err := f.writer.Flush(); err != nil {
+		return err
+	}
+
+	// Content written in append mode is committed by the filer as each chunk is written.
+	if !f.modified || f.flag&fs.O_APPEND != 0 {
+		return nil
+	}
+
+	if err := f.let.cluster.Filer().Update(f.ctx, f.entry); err != nil {
+		return err
+	}
+	f.modified = false
+	return nil
+}
+
+func (f *File) truncate(size int64) error {
+	if size < 0 {
+		return gofs.ErrInvalid
+	}
+
+	if f.writer != nil {
+		if err := f.writer.Flush(); err != nil {
+			return err
+		}
+	}
+
+	// Content written in append mode is committed by the filer, so the entry for the File does not reflect the chunks
+	// that have been appended.
+	if f.flag&fs.O_APPEND != 0 {
+		_, err := f.let.cluster.Truncate(f.ctx, fsPath(f.let, f.entry.Path()), size)
+		return err
+	}
+	return f.let.cluster.TruncateEntry(f.ctx, f.entry, size)
+}
+
+// createPlacement returns the filer.Placement options for creating the entry for the File, where the placement and
+// time-to-live for the File take precedence over those for the Lettuce.
+func (f *File) createPlacement() []func(*filer.Placement) {
+	p := append([]func(*filer.Placement){filer.WithTTL(f.let.ttl)}, f.let.placement...)
+	if f.ttl != nil {
+		p = append(p, filer.WithTTL(*f.ttl))
+	}
+	return append(p, f.placement...)
+}
+
+// assignVolume assigns a volume server for a chunk of the File using the placement for the File, which takes precedence
+// over the placement for the Lettuce. The time-to-live is always the same as the entry for the File.
+func (f *File) assignVolume(ctx context.Context, path string) (string, url.URL, error) {
+	p := append(slices.Clone(f.let.placement), f.placement...)
+	return f.let.cluster.Filer().AssignVolume(ctx, path, append(p, filer.WithTTL(f.entry.TTL()))...)
+}
+
+// uninline moves content stored inline with the entry for the File to a chunk, so that it can be modified by writes.
+func (f *File) uninline() error {
+	b := f.entry.Content()
+	if len(b) == 0 {
+		return nil
+	}
+	size := f.entry.Size()
+
+	f.entry.SetContent(nil)
+	w, err := chunk.NewWriter(f.entry.Path().String(), f.assignVolume, f.writerOptions()...)
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.Write(b); err != nil {
+		return errors.Join(err, w.Close())
+	}
+
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	if _, err := f.entry.Truncate(size); err != nil {
+		return err
+	}
+	return f.let.cluster.Filer().Update(f.ctx, f.entry)
+}
+
+// writerOptions returns the options for the chunk.Writer used for writing content to chunks for the File, which apply
+// the compression and encryption for the File to the content.
+func (f *File) writerOptions() []func(*chunk.Writer) {
+	c := f.let.compress.forName(f.entry.Name())
+	if f.compress != nil {
+		c = *f.compress
+	}
+
+	return []func(*chunk.Writer){
+		chunk.WithWriterChunks(f.entry.Chunks()),
+		chunk.WithWriterCompression(c),
+		chunk.WithWriterContext(f.ctx),
+		chunk.WithWriterEncryption(f.let.encrypt),
+	}
+}
+
+// sniffWriter writes to the writer for a File, setting the MIME type for the File from the content written at the
+// start of the File (see File.sniff).
+type sniffWriter struct {
+	f   *File
+	off int64
+}
+
+// Write writes len(b) bytes to the writer for the File.
+func (w *sniffWriter) Write(b []byte) (int, error) {
+	n, err := w.f.writer.Write(b)
+	w.f.sniff(w.off, b[:n])
+	w.off += int64(n)
+	return n, err
+}
+
+// content is the content stored inline with an entry, which reads as zeros past its end.
+type content []byte
+
+// ReadAt reads len(b) bytes from the content starting at byte offset off.
+func (c content) ReadAt(b []byte, off int64) (int, error) {
+	var n int
+	if off < int64(len(c)) {
+		n = copy(b, c[off:])
+	}
+	clear(b[n:])
+	return len(b), nil
+}
+
+// contentReader reads content stored inline with an entry.
+type contentReader struct {
+	*io.SectionReader
+}
+
+// Close is a no-op, since the content is held in memory.
+func (r *contentReader) Close() error {
+	return nil
+}
@@ -0,0 +1,38 @@
+//go:build integration
+
+package lettuce
+
+import (
+	"context"
+	"testing"
+
+	"github.com/transientvariable/fs-go"
+	"github.com/transientvariable/lettuce/cluster/filer"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileOpenKeepsInlineContent(t *testing.T) {
+	let, err := New(WithInlineSize(1024))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = let.Close() })
+
+	ctx := context.Background()
+	name := storagePrefix + "/inline_open.txt"
+	content := []byte("inline content")
+	require.NoError(t, let.WriteFileContext(ctx, name, content, modeCreate))
+	t.Cleanup(func() { _ = let.RemoveContext(ctx, name) })
+
+	f, err := let.OpenFileContext(ctx, name, fs.O_RDWR, 0)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	fi, err := let.StatContext(ctx, name)
+	require.NoError(t, err)
+
+	e, ok := fi.Sys().(*filer.Entry)
+	require.True(t, ok)
+	assert.Equal(t, content, e.Content())
+	assert.Zero(t, e.Chunks().Len())
+}
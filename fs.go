@@ -35,10 +35,15 @@ var (
 type Lettuce struct {
 	closed     bool
 	cluster    *cluster.Cluster
+	compress   compressionPolicy
+	encrypt    bool
 	entry      *filer.Entry
 	gid        int32
 	httpClient *gohttp.Client
+	inlineSize int
 	mutex      sync.Mutex
+	placement  []func(*filer.Placement)
+	ttl        time.Duration
 	uid        int32
 }
 
@@ -70,6 +75,28 @@ func New(options ...func(*Lettuce)) (*Lettuce, error) {
 	return let, nil
 }
 
+// AppendFile appends data to the named file, creating it with mode if it does not exist.
+//
+// All chunks written for data are committed to the file using a single append, so content appended concurrently by
+// other clients is never interleaved with data.
+func (l *Lettuce) AppendFile(name string, data []byte, mode gofs.FileMode) error {
+	return l.AppendFileContext(context.Background(), name, data, mode)
+}
+
+// AppendFileContext is like AppendFile, but uses the provided context.Context for the operation.
+func (l *Lettuce) AppendFileContext(ctx context.Context, name string, data []byte, mode gofs.FileMode) error {
+	log.Debug("[lettuce] appendFile",
+		log.String("name", name),
+		log.Int("content_length", len(data)),
+		log.String("mode", mode.String()),
+	)
+
+	if err := appendFile(ctx, l, name, data, mode); err != nil {
+		return fmt.Errorf("lettuce: %w", &gofs.PathError{Op: "appendFile", Path: name, Err: err})
+	}
+	return nil
+}
+
 // Close releases any resources used by Lettuce.
 func (l *Lettuce) Close() error {
 	log.Debug("[lettuce] close")
@@ -105,10 +132,12 @@ func (l *Lettuce) Cluster() *cluster.Cluster {
 
 // Create ...
 func (l *Lettuce) Create(name string) (fs.File, error) {
-	log.Debug("[lettuce] create", log.String("name", name))
+	return l.CreateContext(context.Background(), name)
+}
 
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+// CreateContext is like Create, but uses the provided context.Context for the operation and the returned File.
+func (l *Lettuce) CreateContext(ctx context.Context, name string) (fs.File, error) {
+	log.Debug("[lettuce] create", log.String("name", name))
 
 	f, err := open(ctx, l, name, fs.O_RDWR|fs.O_CREATE|fs.O_TRUNC, modeCreate)
 	if err != nil {
@@ -117,33 +146,25 @@ func (l *Lettuce) Create(name string) (fs.File, error) {
 	return f, nil
 }
 
-// Glob ...
+// Glob returns the names of all files matching pattern or nil if there is no matching file. The syntax of patterns is
+// the same as in path.Match, with the addition of "**", which matches zero or more directories when used as a complete
+// path element (e.g. "logs/**/*.gz").
+//
+// Only the directories that can contain matching files are listed. The subtree of a directory matched by the elements
+// preceding "**" is retrieved using Walk, so it does not require a request per directory.
 func (l *Lettuce) Glob(pattern string) ([]string, error) {
-	log.Debug("[lettuce] glob", log.String("pattern", pattern))
-
-	var matches []string
-	err := gofs.WalkDir(l, `.`, func(path string, entry gofs.DirEntry, err error) error {
-		if err != nil {
-			return err
-		}
-
-		if fs.EndsWithDot(l, path) {
-			return nil
-		}
+	return l.GlobContext(context.Background(), pattern)
+}
 
-		matched, err := filepath.Match(pattern, path)
-		if err != nil {
-			return err
-		}
+// GlobContext is like Glob, but uses the provided context.Context when walking the file system.
+func (l *Lettuce) GlobContext(ctx context.Context, pattern string) ([]string, error) {
+	log.Debug("[lettuce] glob", log.String("pattern", pattern))
 
-		if matched {
-			matches = append(matches, path)
-		}
-		return nil
-	})
+	matches, err := glob(ctx, l, pattern)
 	if err != nil {
-		if !errors.Is(err, &gofs.PathError{}) {
-			return matches, fmt.Errorf("lettuce: %w", &gofs.PathError{Op: "glob", Err: err})
+		var pathErr *gofs.PathError
+		if !errors.As(err, &pathErr) {
+			return matches, fmt.Errorf("lettuce: %w", &gofs.PathError{Op: "glob", Path: pattern, Err: err})
 		}
 		return matches, err
 	}
@@ -152,10 +173,12 @@ func (l *Lettuce) Glob(pattern string) ([]string, error) {
 
 // Mkdir creates a new directory with the specified name and permission bits.
 func (l *Lettuce) Mkdir(name string, perm gofs.FileMode) error {
-	log.Debug("[lettuce] mkdir", log.String("name", name))
+	return l.MkdirContext(context.Background(), name, perm)
+}
 
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+// MkdirContext is like Mkdir, but uses the provided context.Context for the operation.
+func (l *Lettuce) MkdirContext(ctx context.Context, name string, perm gofs.FileMode) error {
+	log.Debug("[lettuce] mkdir", log.String("name", name))
 
 	if _, err := mkdir(ctx, l, name, perm); err != nil {
 		return fmt.Errorf("lettuce: %w", &gofs.PathError{Op: "mkdir", Path: name, Err: err})
@@ -165,14 +188,16 @@ func (l *Lettuce) Mkdir(name string, perm gofs.FileMode) error {
 
 // MkdirAll ...
 func (l *Lettuce) MkdirAll(path string, mode gofs.FileMode) error {
+	return l.MkdirAllContext(context.Background(), path, mode)
+}
+
+// MkdirAllContext is like MkdirAll, but uses the provided context.Context for the operation.
+func (l *Lettuce) MkdirAllContext(ctx context.Context, path string, mode gofs.FileMode) error {
 	log.Debug("[lettuce] mkdirAll",
 		log.String("path", path),
 		log.String("mode", mode.String()),
 	)
 
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-
 	if _, err := mkdirAll(ctx, l, path, mode); err != nil {
 		return fmt.Errorf("lettuce: %w", &gofs.PathError{Op: "mkdirAll", Path: path, Err: err})
 	}
@@ -181,10 +206,12 @@ func (l *Lettuce) MkdirAll(path string, mode gofs.FileMode) error {
 
 // Open ...
 func (l *Lettuce) Open(name string) (gofs.File, error) {
-	log.Debug("[lettuce] open", log.String("name", name))
+	return l.OpenContext(context.Background(), name)
+}
 
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+// OpenContext is like Open, but uses the provided context.Context for the operation and the returned File.
+func (l *Lettuce) OpenContext(ctx context.Context, name string) (gofs.File, error) {
+	log.Debug("[lettuce] open", log.String("name", name))
 
 	f, err := open(ctx, l, name, fs.O_RDONLY, 0)
 	if err != nil {
@@ -195,16 +222,25 @@ func (l *Lettuce) Open(name string) (gofs.File, error) {
 
 // OpenFile ...
 func (l *Lettuce) OpenFile(name string, flag int, mode gofs.FileMode) (fs.File, error) {
+	return l.OpenFileContext(context.Background(), name, flag, mode)
+}
+
+// OpenFileContext is like OpenFile, but uses the provided context.Context for the operation and the returned File.
+//
+// Options for the returned File (e.g. WithFileCompression) override the corresponding settings of the Lettuce.
+func (l *Lettuce) OpenFileContext(ctx context.Context,
+	name string,
+	flag int,
+	mode gofs.FileMode,
+	options ...func(*File),
+) (fs.File, error) {
 	log.Debug("[lettuce] openFile",
 		log.String("name", name),
 		log.Int("flag", flag),
 		log.String("mode", mode.String()),
 	)
 
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-
-	f, err := open(ctx, l, name, flag, mode)
+	f, err := open(ctx, l, name, flag, mode, options...)
 	if err != nil {
 		return nil, fmt.Errorf("lettuce: %w", &gofs.PathError{Op: "openFile", Path: name, Err: err})
 	}
@@ -223,15 +259,20 @@ func (l *Lettuce) Provider() string {
 
 // ReadDir ...
 func (l *Lettuce) ReadDir(name string) ([]gofs.DirEntry, error) {
+	return l.ReadDirContext(context.Background(), name)
+}
+
+// ReadDirContext is like ReadDir, but uses the provided context.Context for the operation.
+func (l *Lettuce) ReadDirContext(ctx context.Context, name string) ([]gofs.DirEntry, error) {
 	log.Debug("[lettuce] readDir", log.String("current_dir", l.entry.Name()), log.String("name", name))
 
-	sub, err := l.Sub(name)
+	sub, err := l.SubContext(ctx, name)
 	if err != nil {
 		return nil, err
 	}
 	dir := sub.(*Lettuce)
 
-	ctx, cancel := context.WithCancel(context.Background())
+	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
 	list, err := newDirIterator(ctx, l, dir.entry)
@@ -253,9 +294,14 @@ func (l *Lettuce) ReadDir(name string) ([]gofs.DirEntry, error) {
 
 // ReadFile ...
 func (l *Lettuce) ReadFile(name string) ([]byte, error) {
+	return l.ReadFileContext(context.Background(), name)
+}
+
+// ReadFileContext is like ReadFile, but uses the provided context.Context for the operation.
+func (l *Lettuce) ReadFileContext(ctx context.Context, name string) ([]byte, error) {
 	log.Debug("[lettuce] readFile", log.String("name", name))
 
-	f, err := l.Open(name)
+	f, err := l.OpenContext(ctx, name)
 	if err != nil {
 		return nil, err
 	}
@@ -274,10 +320,12 @@ func (l *Lettuce) ReadFile(name string) ([]byte, error) {
 
 // Remove ...
 func (l *Lettuce) Remove(name string) error {
-	log.Debug("[lettuce] remove", log.String("name", name))
+	return l.RemoveContext(context.Background(), name)
+}
 
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+// RemoveContext is like Remove, but uses the provided context.Context for the operation.
+func (l *Lettuce) RemoveContext(ctx context.Context, name string) error {
+	log.Debug("[lettuce] remove", log.String("name", name))
 
 	if err := remove(ctx, l, name); err != nil {
 		return fmt.Errorf("lettuce: %w", &gofs.PathError{Op: "remove", Path: name, Err: err})
@@ -287,10 +335,12 @@ func (l *Lettuce) Remove(name string) error {
 
 // RemoveAll ...
 func (l *Lettuce) RemoveAll(path string) error {
-	log.Debug("[lettuce] removeAll", log.String("path", path))
+	return l.RemoveAllContext(context.Background(), path)
+}
 
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+// RemoveAllContext is like RemoveAll, but uses the provided context.Context for the operation.
+func (l *Lettuce) RemoveAllContext(ctx context.Context, path string) error {
+	log.Debug("[lettuce] removeAll", log.String("path", path))
 
 	if err := removeAll(ctx, l, path); err != nil {
 		return fmt.Errorf("lettuce: %w", &gofs.PathError{Op: "rename", Path: path, Err: err})
@@ -300,14 +350,16 @@ func (l *Lettuce) RemoveAll(path string) error {
 
 // Rename ...
 func (l *Lettuce) Rename(oldpath string, newpath string) error {
+	return l.RenameContext(context.Background(), oldpath, newpath)
+}
+
+// RenameContext is like Rename, but uses the provided context.Context for the operation.
+func (l *Lettuce) RenameContext(ctx context.Context, oldpath string, newpath string) error {
 	log.Debug("[lettuce] rename",
 		log.String("old_path", oldpath),
 		log.String("new_path", newpath),
 	)
 
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-
 	if err := rename(ctx, l, oldpath, newpath); err != nil {
 		return fmt.Errorf("lettuce: %w", &goos.LinkError{Op: "rename", Old: oldpath, New: newpath, Err: err})
 	}
@@ -319,31 +371,48 @@ func (l *Lettuce) Root() (string, error) {
 	return l.cluster.Filer().Root().Path().Name(), nil
 }
 
-// Stat ...
+// Stat returns a fs.FileInfo describing the named file.
+//
+// The dynamic type of the fs.FileInfo is not *fs.Entry. It reports the number of hard links to the file (see Links),
+// and its Sys method returns the *filer.Entry for the file, which describes other SeaweedFS specific metadata and can
+// be converted using FSEntry.
 func (l *Lettuce) Stat(name string) (gofs.FileInfo, error) {
-	log.Debug("[lettuce] stat", log.String("name", name))
+	return l.StatContext(context.Background(), name)
+}
 
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+// StatContext is like Stat, but uses the provided context.Context for the operation.
+func (l *Lettuce) StatContext(ctx context.Context, name string) (gofs.FileInfo, error) {
+	log.Debug("[lettuce] stat", log.String("name", name))
 
 	fe, err := stat(ctx, l, name)
 	if err != nil {
 		return nil, fmt.Errorf("lettuce: %w", &gofs.PathError{Op: "stat", Path: name, Err: err})
 	}
 
-	e, err := FSEntry(l, fe)
+	e, err := newFileInfo(l, fe)
 	if err != nil {
 		return nil, fmt.Errorf("lettuce: %w", &gofs.PathError{Op: "stat", Path: name, Err: err})
 	}
+
+	// Entries resolved through a symbolic link retain the path they were requested with.
+	if n, err := fs.CleanPath(l, name); err == nil {
+		if p := lpath(l, n); p != e.Path() {
+			if err := e.SetPath(p); err != nil {
+				return nil, fmt.Errorf("lettuce: %w", &gofs.PathError{Op: "stat", Path: name, Err: err})
+			}
+		}
+	}
 	return e, nil
 }
 
 // Sub ...
 func (l *Lettuce) Sub(dir string) (gofs.FS, error) {
-	log.Debug("[lettuce] sub", log.String("current", l.entry.Name()), log.String("dir", dir))
+	return l.SubContext(context.Background(), dir)
+}
 
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+// SubContext is like Sub, but uses the provided context.Context for the operation.
+func (l *Lettuce) SubContext(ctx context.Context, dir string) (gofs.FS, error) {
+	log.Debug("[lettuce] sub", log.String("current", l.entry.Name()), log.String("dir", dir))
 
 	sub, err := sub(ctx, l, dir)
 	if err != nil {
@@ -352,17 +421,38 @@ func (l *Lettuce) Sub(dir string) (gofs.FS, error) {
 	return sub, nil
 }
 
+// Truncate changes the size of the named file. If the file is a symbolic link, the size of the link's target is
+// changed.
+//
+// If the file is shrunk, data past the new size is discarded. If the file is extended, the extended part reads as
+// zeros and does not consume storage.
+func (l *Lettuce) Truncate(name string, size int64) error {
+	return l.TruncateContext(context.Background(), name, size)
+}
+
+// TruncateContext is like Truncate, but uses the provided context.Context for the operation.
+func (l *Lettuce) TruncateContext(ctx context.Context, name string, size int64) error {
+	log.Debug("[lettuce] truncate", log.String("name", name), log.Int64("size", size))
+
+	if err := truncate(ctx, l, name, size); err != nil {
+		return fmt.Errorf("lettuce: %w", &gofs.PathError{Op: "truncate", Path: name, Err: err})
+	}
+	return nil
+}
+
 // WriteFile ...
 func (l *Lettuce) WriteFile(name string, data []byte, mode gofs.FileMode) error {
+	return l.WriteFileContext(context.Background(), name, data, mode)
+}
+
+// WriteFileContext is like WriteFile, but uses the provided context.Context for the operation.
+func (l *Lettuce) WriteFileContext(ctx context.Context, name string, data []byte, mode gofs.FileMode) error {
 	log.Debug("[lettuce] writeFile",
 		log.String("name", name),
 		log.Int("content_length", len(data)),
 		log.String("mode", mode.String()),
 	)
 
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-
 	f, err := open(ctx, l, name, fs.O_RDWR|fs.O_CREATE|fs.O_TRUNC, mode)
 	if err != nil {
 		return fmt.Errorf("lettuce: %w", &gofs.PathError{Op: "writeFile", Path: name, Err: err})
@@ -421,7 +511,28 @@ func (l *Lettuce) path() (string, error) {
 	return "", errors.New("lettuce: path not found")
 }
 
-func create(ctx context.Context, let *Lettuce, name string, flag int, mode gofs.FileMode) (*File, error) {
+func appendFile(ctx context.Context, let *Lettuce, name string, data []byte, mode gofs.FileMode) error {
+	f, err := open(ctx, let, name, fs.O_WRONLY|fs.O_CREATE|fs.O_APPEND, mode)
+	if err != nil {
+		return err
+	}
+	f.deferred = true
+
+	if len(data) > 0 {
+		if _, err := f.Write(data); err != nil {
+			return errors.Join(err, f.Close())
+		}
+	}
+	return f.Close()
+}
+
+func create(ctx context.Context,
+	let *Lettuce,
+	name string,
+	flag int,
+	mode gofs.FileMode,
+	options ...func(*File),
+) (*File, error) {
 	if mode&gofs.ModeDir != 0 {
 		log.Trace("[lettuce] directory mode bits set, creating path as directory", log.String("name", name))
 
@@ -430,7 +541,7 @@ func create(ctx context.Context, let *Lettuce, name string, flag int, mode gofs.
 			return nil, err
 		}
 
-		file, err := newFile(dir, flag)
+		file, err := newFile(dir, flag, WithContext(ctx))
 		if err != nil {
 			return nil, err
 		}
@@ -450,11 +561,22 @@ func create(ctx context.Context, let *Lettuce, name string, flag int, mode gofs.
 		let = dir
 	}
 
-	e, err := let.cluster.Filer().Create(ctx, name, mode)
+	// The options are applied before the entry is created, since they include the placement for the entry.
+	f := &File{let: let, flag: flag}
+	for _, opt := range append([]func(*File){WithContext(ctx)}, options...) {
+		opt(f)
+	}
+
+	e, err := let.cluster.Filer().Create(ctx, name, mode, f.createPlacement()...)
 	if err != nil {
 		return nil, err
 	}
-	return newFile(let, flag, WithEntry(e))
+	f.entry = e
+
+	if err := f.init(); err != nil {
+		return nil, err
+	}
+	return f, nil
 }
 
 func mkdir(ctx context.Context, let *Lettuce, name string, mode gofs.FileMode) (*Lettuce, error) {
@@ -476,7 +598,7 @@ func mkdir(ctx context.Context, let *Lettuce, name string, mode gofs.FileMode) (
 		return nil, gofs.ErrInvalid
 	}
 
-	if _, err := stat(ctx, let, n); err != nil {
+	if _, err := lstat(ctx, let, n); err != nil {
 		if !errors.Is(err, gofs.ErrNotExist) {
 			return nil, err
 		}
@@ -495,12 +617,7 @@ func mkdir(ctx context.Context, let *Lettuce, name string, mode gofs.FileMode) (
 	if err != nil {
 		return nil, err
 	}
-	return &Lettuce{
-		cluster: let.cluster,
-		entry:   e,
-		gid:     let.gid,
-		uid:     let.uid,
-	}, nil
+	return derive(let, e), nil
 }
 
 func mkdirAll(ctx context.Context, let *Lettuce, path string, mode gofs.FileMode) (*Lettuce, error) {
@@ -529,7 +646,13 @@ func mkdirAll(ctx context.Context, let *Lettuce, path string, mode gofs.FileMode
 	return let, nil
 }
 
-func open(ctx context.Context, let *Lettuce, name string, flag int, mode gofs.FileMode) (*File, error) {
+func open(ctx context.Context,
+	let *Lettuce,
+	name string,
+	flag int,
+	mode gofs.FileMode,
+	options ...func(*File),
+) (*File, error) {
 	log.Trace("[lettuce] open",
 		log.String("name", name),
 		log.Int("flag", flag),
@@ -541,26 +664,31 @@ func open(ctx context.Context, let *Lettuce, name string, flag int, mode gofs.Fi
 	}
 
 	if name == "." {
-		return newFile(let, fs.O_RDONLY)
+		return newFile(let, fs.O_RDONLY, WithContext(ctx))
 	}
 
-	e, err := stat(ctx, let, name)
+	e, p, err := evalSymlinks(ctx, let, name)
 	if err != nil {
 		if errors.Is(err, gofs.ErrNotExist) && flag&fs.O_CREATE != 0 {
 			log.Trace("[lettuce] creating new file", log.String("name", name))
-			return create(ctx, let, name, flag, mode)
+
+			if p != lpath(let, name) {
+				log.Trace("[lettuce] creating new file at symlink target", log.String("target", p))
+				return create(ctx, rootOf(let), p, flag, mode, options...)
+			}
+			return create(ctx, let, name, flag, mode, options...)
 		}
 		return nil, err
 	}
 
 	if !e.IsDir() {
-		return newFile(let, flag, WithEntry(e))
+		return newFile(let, flag, append([]func(*File){WithContext(ctx), WithEntry(e)}, options...)...)
 	}
-	return newFile(let, fs.O_RDONLY, WithEntry(e))
+	return newFile(let, fs.O_RDONLY, WithContext(ctx), WithEntry(e))
 }
 
 func remove(ctx context.Context, let *Lettuce, name string) error {
-	fi, err := stat(ctx, let, name)
+	fi, err := lstat(ctx, let, name)
 	if err != nil {
 		if !errors.Is(err, gofs.ErrNotExist) {
 			return err
@@ -579,7 +707,7 @@ func remove(ctx context.Context, let *Lettuce, name string) error {
 }
 
 func removeAll(ctx context.Context, let *Lettuce, path string) error {
-	e, err := stat(ctx, let, path)
+	e, err := lstat(ctx, let, path)
 	if err != nil {
 		if !errors.Is(err, gofs.ErrNotExist) {
 			return err
@@ -619,58 +747,136 @@ func rename(ctx context.Context, let *Lettuce, oldpath string, newpath string) e
 	return let.cluster.Filer().Rename(ctx, o, n)
 }
 
-func stat(ctx context.Context, let *Lettuce, name string) (*filer.Entry, error) {
-	name, err := fs.CleanPath(let, name)
+func sub(ctx context.Context, let *Lettuce, dir string) (*Lettuce, error) {
+	e, err := stat(ctx, let, dir)
 	if err != nil {
 		return nil, err
 	}
 
-	if name == "." {
-		return let.entry, nil
-	}
-
-	r, err := let.Root()
-	if err != nil {
-		return nil, err
+	if !e.IsDir() {
+		return nil, fs.ErrNotDir
 	}
+	return derive(let, e), nil
+}
 
-	if let.entry.Name() != r {
-		name = strings.Join([]string{let.entry.Name(), name}, let.PathSeparator())
+func truncate(ctx context.Context, let *Lettuce, name string, size int64) error {
+	if size < 0 {
+		return gofs.ErrInvalid
 	}
-	return let.cluster.Filer().Stat(ctx, name)
-}
 
-func sub(ctx context.Context, let *Lettuce, dir string) (*Lettuce, error) {
-	e, err := stat(ctx, let, dir)
+	e, err := stat(ctx, let, name)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	if !e.IsDir() {
-		return nil, fs.ErrNotDir
+	if e.IsDir() {
+		return fs.ErrIsDir
 	}
+	return let.cluster.TruncateEntry(ctx, e, size)
+}
+
+// derive returns a Lettuce for the directory represented by the provided filer.Entry, which shares the configuration
+// of let.
+func derive(let *Lettuce, e *filer.Entry) *Lettuce {
 	return &Lettuce{
 		cluster:    let.cluster,
+		compress:   let.compress,
+		encrypt:    let.encrypt,
 		entry:      e,
 		gid:        let.gid,
 		httpClient: let.httpClient,
+		inlineSize: let.inlineSize,
+		placement:  let.placement,
+		ttl:        let.ttl,
 		uid:        let.uid,
-	}, nil
+	}
+}
+
+// fileInfo extends an fs.Entry with the filer.Entry it was created from.
+type fileInfo struct {
+	*fs.Entry
+	filerEntry *filer.Entry
+}
+
+// newFileInfo creates a fileInfo for the provided filer.Entry.
+func newFileInfo(fsys fs.FS, filerEntry *filer.Entry) (*fileInfo, error) {
+	e, err := FSEntry(fsys, filerEntry)
+	if err != nil {
+		return nil, err
+	}
+	return &fileInfo{Entry: e, filerEntry: filerEntry}, nil
+}
+
+// Links returns the number of hard links to the file described by the fileInfo.
+func (fi *fileInfo) Links() int {
+	return fi.filerEntry.Links()
+}
+
+// Sys returns the filer.Entry the fileInfo was created from, which can be used for retrieving SeaweedFS specific
+// metadata (e.g. the number of hard links).
+func (fi *fileInfo) Sys() any {
+	return fi.filerEntry
 }
 
-// FSEntry converts a filer.Entry to an fs.Entry.
+// Links returns the number of hard links to the file described by the provided fs.FileInfo, and whether the number is
+// known. The number is known for a fs.FileInfo returned by Lettuce.Stat, Lettuce.Lstat or File.Stat, but not for the
+// *fs.Entry values provided by Lettuce.ReadDir, Lettuce.Walk or Lettuce.Watch, since fs.Entry does not describe it.
+func Links(fi gofs.FileInfo) (int, bool) {
+	l, ok := fi.(interface{ Links() int })
+	if !ok {
+		return 0, false
+	}
+	return l.Links(), true
+}
+
+// TTL returns the remaining lifetime for the file described by the provided fs.FileInfo, and whether the file expires.
+// The fs.FileInfo must be one returned by Lettuce (e.g. Lettuce.Stat or File.Stat), since fs.Entry does not describe
+// the time-to-live for a file.
+func TTL(fi gofs.FileInfo) (time.Duration, bool) {
+	e, ok := fi.Sys().(*filer.Entry)
+	if !ok {
+		return 0, false
+	}
+
+	t, ok := e.Expires()
+	if !ok {
+		return 0, false
+	}
+	return max(time.Until(t), 0), true
+}
+
+// FSEntry converts a filer.Entry to an fs.Entry. SeaweedFS specific metadata (e.g. the number of hard links, see
+// Links) is not described by the fs.Entry.
 func FSEntry(fsys fs.FS, filerEntry *filer.Entry, options ...func(*fs.Entry)) (*fs.Entry, error) {
 	if fsys == nil {
 		return nil, errors.New("file system is required")
 	}
 
-	attrs := []func(*fs.Attribute){fs.WithSize(uint64(filerEntry.Size()))}
+	size := uint64(filerEntry.Size())
+	if filerEntry.IsSymlink() {
+		size = uint64(len(filerEntry.SymlinkTarget()))
+	}
+
+	attrs := []func(*fs.Attribute){fs.WithSize(size)}
 	if pbAttrs := filerEntry.PB().GetAttributes(); pbAttrs != nil {
+		mode := pbAttrs.GetFileMode()
+		if filerEntry.IsSymlink() {
+			mode |= uint32(gofs.ModeSymlink)
+		}
+
+		// Entries may have a modification time that predates the creation time (e.g. when the modification time has
+		// been restored from a backup), which fs.Attribute does not permit. The creation time is unknown to the
+		// fs.Attribute in that case, so it is left unset (i.e. the Unix epoch) as for entries without a creation time.
+		ctime := pbAttrs.GetCrtime()
+		if ctime > pbAttrs.GetMtime() {
+			ctime = 0
+		}
+
 		attrs = append(attrs,
-			fs.WithCtime(time.Unix(pbAttrs.GetCrtime(), 0)),
+			fs.WithCtime(time.Unix(ctime, 0)),
 			fs.WithGID(pbAttrs.GetGid()),
 			fs.WithInode(pbAttrs.GetInode()),
-			fs.WithMode(pbAttrs.GetFileMode()),
+			fs.WithMode(mode),
 			fs.WithMtime(time.Unix(pbAttrs.GetMtime(), 0)),
 			fs.WithOwner(pbAttrs.GetUserName()),
 			fs.WithUID(pbAttrs.GetUid()))
@@ -0,0 +1,159 @@
+package lettuce
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/transientvariable/lettuce/cluster/filer"
+	"github.com/transientvariable/log-go"
+
+	gofs "io/fs"
+)
+
+// Chcrtime changes the creation time of the named file. If the file is a symbolic link, the creation time of the link's
+// target is changed.
+//
+// The creation time cannot be later than the modification time of the file.
+func (l *Lettuce) Chcrtime(name string, crtime time.Time) error {
+	return l.ChcrtimeContext(context.Background(), name, crtime)
+}
+
+// ChcrtimeContext is like Chcrtime, but uses the provided context.Context for the operation.
+func (l *Lettuce) ChcrtimeContext(ctx context.Context, name string, crtime time.Time) error {
+	log.Debug("[lettuce] chcrtime", log.String("name", name), log.Time("crtime", crtime))
+
+	if err := chattr(ctx, l, name, true, func(e *filer.Entry) error { return chcrtime(e, crtime) }); err != nil {
+		return fmt.Errorf("lettuce: %w", &gofs.PathError{Op: "chcrtime", Path: name, Err: err})
+	}
+	return nil
+}
+
+// Chmod changes the mode of the named file to mode. If the file is a symbolic link, the mode of the link's target is
+// changed.
+//
+// Only the permission bits, and the setuid, setgid and sticky bits of mode are used.
+func (l *Lettuce) Chmod(name string, mode gofs.FileMode) error {
+	return l.ChmodContext(context.Background(), name, mode)
+}
+
+// ChmodContext is like Chmod, but uses the provided context.Context for the operation.
+func (l *Lettuce) ChmodContext(ctx context.Context, name string, mode gofs.FileMode) error {
+	log.Debug("[lettuce] chmod", log.String("name", name), log.String("mode", mode.String()))
+
+	if err := chattr(ctx, l, name, true, func(e *filer.Entry) error { return chmod(e, mode) }); err != nil {
+		return fmt.Errorf("lettuce: %w", &gofs.PathError{Op: "chmod", Path: name, Err: err})
+	}
+	return nil
+}
+
+// Chown changes the numeric uid and gid of the named file. If the file is a symbolic link, the uid and gid of the
+// link's target are changed.
+//
+// A uid or gid of -1 means to not change that value.
+func (l *Lettuce) Chown(name string, uid int, gid int) error {
+	return l.ChownContext(context.Background(), name, uid, gid)
+}
+
+// ChownContext is like Chown, but uses the provided context.Context for the operation.
+func (l *Lettuce) ChownContext(ctx context.Context, name string, uid int, gid int) error {
+	log.Debug("[lettuce] chown", log.String("name", name), log.Int("uid", uid), log.Int("gid", gid))
+
+	if err := chattr(ctx, l, name, true, func(e *filer.Entry) error { return chown(e, uid, gid) }); err != nil {
+		return fmt.Errorf("lettuce: %w", &gofs.PathError{Op: "chown", Path: name, Err: err})
+	}
+	return nil
+}
+
+// Chtimes changes the modification time of the named file. If the file is a symbolic link, the modification time of
+// the link's target is changed.
+//
+// The filer does not track access times, so atime is ignored. A zero time.Time value for mtime leaves the modification
+// time unchanged. The creation time is never changed, since the time-to-live of a file is measured from it.
+func (l *Lettuce) Chtimes(name string, atime time.Time, mtime time.Time) error {
+	return l.ChtimesContext(context.Background(), name, atime, mtime)
+}
+
+// ChtimesContext is like Chtimes, but uses the provided context.Context for the operation.
+func (l *Lettuce) ChtimesContext(ctx context.Context, name string, atime time.Time, mtime time.Time) error {
+	log.Debug("[lettuce] chtimes", log.String("name", name), log.Time("mtime", mtime))
+
+	if err := chattr(ctx, l, name, true, func(e *filer.Entry) error { return chtimes(e, mtime) }); err != nil {
+		return fmt.Errorf("lettuce: %w", &gofs.PathError{Op: "chtimes", Path: name, Err: err})
+	}
+	return nil
+}
+
+// Lchown changes the numeric uid and gid of the named file. If the file is a symbolic link, the uid and gid of the
+// link itself are changed.
+func (l *Lettuce) Lchown(name string, uid int, gid int) error {
+	return l.LchownContext(context.Background(), name, uid, gid)
+}
+
+// LchownContext is like Lchown, but uses the provided context.Context for the operation.
+func (l *Lettuce) LchownContext(ctx context.Context, name string, uid int, gid int) error {
+	log.Debug("[lettuce] lchown", log.String("name", name), log.Int("uid", uid), log.Int("gid", gid))
+
+	if err := chattr(ctx, l, name, false, func(e *filer.Entry) error { return chown(e, uid, gid) }); err != nil {
+		return fmt.Errorf("lettuce: %w", &gofs.PathError{Op: "lchown", Path: name, Err: err})
+	}
+	return nil
+}
+
+func chattr(ctx context.Context, let *Lettuce, name string, follow bool, fn func(*filer.Entry) error) error {
+	var e *filer.Entry
+	var err error
+	if follow {
+		e, err = stat(ctx, let, name)
+	} else {
+		e, err = lstat(ctx, let, name)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := fn(e); err != nil {
+		return err
+	}
+	return let.cluster.Filer().Update(ctx, e)
+}
+
+func chcrtime(e *filer.Entry, crtime time.Time) error {
+	if crtime.Unix() > e.ModTime().Unix() {
+		return gofs.ErrInvalid
+	}
+	e.SetCrtime(crtime)
+	return nil
+}
+
+func chmod(e *filer.Entry, mode gofs.FileMode) error {
+	e.SetMode(mode)
+	return nil
+}
+
+func chown(e *filer.Entry, uid int, gid int) error {
+	if uid < -1 || gid < -1 {
+		return gofs.ErrInvalid
+	}
+
+	u := uint32(e.UID())
+	if uid != -1 {
+		u = uint32(uid)
+	}
+
+	g := uint32(e.GID())
+	if gid != -1 {
+		g = uint32(gid)
+	}
+	e.SetOwner(u, g)
+	return nil
+}
+
+func chtimes(e *filer.Entry, mtime time.Time) error {
+	if mtime.IsZero() {
+		return nil
+	}
+
+	e.SetModTime(mtime)
+	return nil
+}
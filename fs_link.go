@@ -0,0 +1,65 @@
+package lettuce
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/transientvariable/fs-go"
+	"github.com/transientvariable/log-go"
+
+	gofs "io/fs"
+	goos "os"
+)
+
+// Link creates newname as a hard link to the oldname file.
+//
+// Symbolic links are not followed, so if oldname is a symbolic link, newname will be a hard link to the symbolic
+// link. The volume data for a file is only deleted once all of its hard links have been removed.
+//
+// The number of hard links to a file is reported by the fs.FileInfo returned by Stat, Lstat or File.Stat (see Links).
+func (l *Lettuce) Link(oldname string, newname string) error {
+	return l.LinkContext(context.Background(), oldname, newname)
+}
+
+// LinkContext is like Link, but uses the provided context.Context for the operation.
+func (l *Lettuce) LinkContext(ctx context.Context, oldname string, newname string) error {
+	log.Debug("[lettuce] link",
+		log.String("old_name", oldname),
+		log.String("new_name", newname),
+	)
+
+	if err := link(ctx, l, oldname, newname); err != nil {
+		return fmt.Errorf("lettuce: %w", &goos.LinkError{Op: "link", Old: oldname, New: newname, Err: err})
+	}
+	return nil
+}
+
+func link(ctx context.Context, let *Lettuce, oldname string, newname string) error {
+	o, err := fs.CleanPath(let, oldname)
+	if err != nil {
+		return err
+	}
+
+	n, err := fs.CleanPath(let, newname)
+	if err != nil {
+		return err
+	}
+
+	if fs.EndsWithDot(let, o) || fs.EndsWithDot(let, n) {
+		return gofs.ErrInvalid
+	}
+
+	e, err := lstat(ctx, let, o)
+	if err != nil {
+		return err
+	}
+
+	if e.IsDir() {
+		return gofs.ErrPermission
+	}
+
+	if _, err := let.cluster.Filer().Link(ctx, lpath(let, o), lpath(let, n)); err != nil {
+		return err
+	}
+	return nil
+}
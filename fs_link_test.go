@@ -0,0 +1,48 @@
+//go:build integration
+
+package lettuce
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLinks(t *testing.T) {
+	let, err := New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = let.Close() })
+
+	ctx := context.Background()
+	name := storagePrefix + "/links.txt"
+	link := storagePrefix + "/links_link.txt"
+	require.NoError(t, let.WriteFileContext(ctx, name, []byte("links"), modeCreate))
+	t.Cleanup(func() { _ = let.RemoveContext(ctx, name) })
+
+	fi, err := let.StatContext(ctx, name)
+	require.NoError(t, err)
+	n, ok := Links(fi)
+	require.True(t, ok)
+	assert.Equal(t, 1, n)
+
+	require.NoError(t, let.LinkContext(ctx, name, link))
+	t.Cleanup(func() { _ = let.RemoveContext(ctx, link) })
+
+	for _, s := range []string{name, link} {
+		fi, err := let.StatContext(ctx, s)
+		require.NoError(t, err)
+		n, ok := Links(fi)
+		require.True(t, ok)
+		assert.Equal(t, 2, n)
+	}
+
+	entries, err := let.ReadDir(storagePrefix)
+	require.NoError(t, err)
+	require.NotEmpty(t, entries)
+	info, err := entries[0].Info()
+	require.NoError(t, err)
+	_, ok = Links(info)
+	assert.False(t, ok)
+}
@@ -0,0 +1,129 @@
+package lettuce
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"math"
+
+	"github.com/transientvariable/fs-go"
+	"github.com/transientvariable/lettuce/cluster/filer"
+	"github.com/transientvariable/log-go"
+
+	gofs "io/fs"
+)
+
+// ListOptions defines the options for listing the entries in a directory using Lettuce.List.
+type ListOptions struct {
+	// Inclusive sets whether the entry named StartFrom is included in the listing.
+	Inclusive bool
+
+	// Limit sets the maximum number of entries returned. If less than or equal to zero, at most 1024 entries are
+	// returned.
+	Limit int
+
+	// Prefix restricts the listing to entries with names that start with the prefix.
+	Prefix string
+
+	// StartFrom sets the name of the entry the listing starts after (or at, see Inclusive). Entries are listed in
+	// lexical order by name. StartFrom is ignored if Token is set.
+	StartFrom string
+
+	// Token is the continuation token returned by a previous call to Lettuce.List, which resumes the listing after the
+	// last entry that was returned. The same Prefix must be used with the Token.
+	Token string
+}
+
+// List returns a page of entries for the named directory using the provided ListOptions, along with a continuation
+// token that can be used for retrieving the next page (see ListOptions.Token). If there are no remaining entries, the
+// returned token is empty.
+func (l *Lettuce) List(name string, opts ListOptions) ([]gofs.DirEntry, string, error) {
+	return l.ListContext(context.Background(), name, opts)
+}
+
+// ListContext is like List, but uses the provided context.Context for the operation.
+func (l *Lettuce) ListContext(ctx context.Context, name string, opts ListOptions) ([]gofs.DirEntry, string, error) {
+	log.Debug("[lettuce] list",
+		log.String("name", name),
+		log.String("prefix", opts.Prefix),
+		log.Int("limit", opts.Limit))
+
+	entries, token, err := list(ctx, l, name, opts)
+	if err != nil {
+		return nil, "", fmt.Errorf("lettuce: %w", &gofs.PathError{Op: "list", Path: name, Err: err})
+	}
+	return entries, token, nil
+}
+
+func list(ctx context.Context, let *Lettuce, name string, opts ListOptions) ([]gofs.DirEntry, string, error) {
+	dir, err := stat(ctx, let, name)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if !dir.IsDir() {
+		return nil, "", fs.ErrNotDir
+	}
+
+	limit := listLimit(opts.Limit)
+	lo := filer.ListOptions{
+		Inclusive: opts.Inclusive,
+		Limit:     uint32(limit) + 1,
+		Prefix:    opts.Prefix,
+		StartFrom: opts.StartFrom,
+	}
+
+	if opts.Token != "" {
+		start, err := decodeListToken(opts.Token)
+		if err != nil {
+			return nil, "", err
+		}
+		lo.Inclusive = false
+		lo.StartFrom = start
+	}
+
+	// One more entry than the limit is requested to determine whether there are remaining entries.
+	fes, err := let.cluster.Filer().List(ctx, dir.Path().String(), lo)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var token string
+	if len(fes) > limit {
+		fes = fes[:limit]
+		token = encodeListToken(fes[limit-1].Name())
+	}
+
+	entries := make([]gofs.DirEntry, len(fes))
+	for i, fe := range fes {
+		e, err := FSEntry(let, fe)
+		if err != nil {
+			return nil, "", err
+		}
+		entries[i] = e
+	}
+	return entries, token, nil
+}
+
+// listLimit returns the maximum number of entries returned by a listing for the provided ListOptions.Limit. The limit
+// is clamped so that requesting one more entry than the limit does not overflow the filer limit.
+func listLimit(limit int) int {
+	if limit <= 0 {
+		return dirBatchSize
+	}
+	return int(min(uint64(limit), math.MaxUint32-1))
+}
+
+// encodeListToken returns the continuation token for a listing that resumes after the entry with the provided name.
+func encodeListToken(name string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(name))
+}
+
+// decodeListToken returns the name of the entry a listing resumes after for the provided continuation token.
+func decodeListToken(token string) (string, error) {
+	start, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(start) == 0 {
+		return "", gofs.ErrInvalid
+	}
+	return string(start), nil
+}
@@ -0,0 +1,61 @@
+package lettuce
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	gofs "io/fs"
+)
+
+func TestListToken(t *testing.T) {
+	tests := []string{"a", "file.txt", "with space", "with/slash", "ünïcödé", "+/=", "\x00\xff"}
+
+	for _, name := range tests {
+		t.Run(name, func(t *testing.T) {
+			token := encodeListToken(name)
+			assert.NotContains(t, token, "=")
+
+			got, err := decodeListToken(token)
+			require.NoError(t, err)
+			assert.Equal(t, name, got)
+		})
+	}
+}
+
+func TestListTokenInvalid(t *testing.T) {
+	tests := []string{"", "!", "YQ==", "a"}
+
+	for _, token := range tests {
+		t.Run(token, func(t *testing.T) {
+			_, err := decodeListToken(token)
+			assert.ErrorIs(t, err, gofs.ErrInvalid)
+		})
+	}
+}
+
+func TestListLimit(t *testing.T) {
+	tests := []struct {
+		limit int
+		want  int
+	}{
+		{limit: -1, want: dirBatchSize},
+		{limit: 0, want: dirBatchSize},
+		{limit: 1, want: 1},
+		{limit: 5000, want: 5000},
+		{limit: math.MaxUint32 - 1, want: math.MaxUint32 - 1},
+		{limit: math.MaxUint32, want: math.MaxUint32 - 1},
+		{limit: math.MaxInt, want: math.MaxUint32 - 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.limit), func(t *testing.T) {
+			got := listLimit(tt.limit)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, uint64(got)+1, uint64(uint32(got)+1))
+		})
+	}
+}
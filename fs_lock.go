@@ -0,0 +1,42 @@
+package lettuce
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/transientvariable/fs-go"
+	"github.com/transientvariable/lettuce/cluster/filer"
+	"github.com/transientvariable/log-go"
+
+	gofs "io/fs"
+)
+
+// Lock acquires a distributed lock for the named file on behalf of owner, blocking until the lock is acquired or the
+// context is done. The file does not need to exist, and the lock does not prevent other clients from modifying it,
+// so all clients that modify the file must acquire the lock first.
+//
+// The lock is renewed in the background every third of ttl until it is released. See filer.Lock for details.
+func (l *Lettuce) Lock(ctx context.Context, name string, ttl time.Duration, owner string) (*filer.Lock, error) {
+	log.Debug("[lettuce] lock", log.String("name", name), log.String("owner", owner))
+
+	lk, err := lock(ctx, l, name, ttl, owner)
+	if err != nil {
+		return nil, fmt.Errorf("lettuce: %w", &gofs.PathError{Op: "lock", Path: name, Err: err})
+	}
+	return lk, nil
+}
+
+func lock(ctx context.Context, let *Lettuce, name string, ttl time.Duration, owner string) (*filer.Lock, error) {
+	n, err := fs.CleanPath(let, name)
+	if err != nil {
+		return nil, err
+	}
+	return let.cluster.Filer().Lock(ctx, lockName(let, n), ttl, owner)
+}
+
+// lockName returns the name of the distributed lock for the named file, which is the full path for the file.
+func lockName(let *Lettuce, name string) string {
+	return path.Join(let.entry.Path().String(), name)
+}
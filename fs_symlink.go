@@ -0,0 +1,241 @@
+package lettuce
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/transientvariable/fs-go"
+	"github.com/transientvariable/lettuce/cluster/filer"
+	"github.com/transientvariable/log-go"
+
+	gofs "io/fs"
+	goos "os"
+)
+
+const (
+	// symlinksMax defines the maximum number of symbolic links that will be followed when resolving a path.
+	symlinksMax = 40
+)
+
+var (
+	_ gofs.ReadLinkFS = (*Lettuce)(nil)
+)
+
+// Lstat returns a fs.FileInfo describing the named file. If the file is a symbolic link, the returned fs.FileInfo
+// describes the symbolic link and no attempt is made to follow it.
+//
+// As for Stat, the fs.FileInfo reports the number of hard links to the file (see Links), and its Sys method returns the
+// *filer.Entry for the file.
+func (l *Lettuce) Lstat(name string) (gofs.FileInfo, error) {
+	return l.LstatContext(context.Background(), name)
+}
+
+// LstatContext is like Lstat, but uses the provided context.Context for the operation.
+func (l *Lettuce) LstatContext(ctx context.Context, name string) (gofs.FileInfo, error) {
+	log.Debug("[lettuce] lstat", log.String("name", name))
+
+	fe, err := lstat(ctx, l, name)
+	if err != nil {
+		return nil, fmt.Errorf("lettuce: %w", &gofs.PathError{Op: "lstat", Path: name, Err: err})
+	}
+
+	e, err := newFileInfo(l, fe)
+	if err != nil {
+		return nil, fmt.Errorf("lettuce: %w", &gofs.PathError{Op: "lstat", Path: name, Err: err})
+	}
+	return e, nil
+}
+
+// ReadLink returns the destination of the named symbolic link.
+func (l *Lettuce) ReadLink(name string) (string, error) {
+	return l.ReadLinkContext(context.Background(), name)
+}
+
+// ReadLinkContext is like ReadLink, but uses the provided context.Context for the operation.
+func (l *Lettuce) ReadLinkContext(ctx context.Context, name string) (string, error) {
+	log.Debug("[lettuce] readLink", log.String("name", name))
+
+	fe, err := lstat(ctx, l, name)
+	if err != nil {
+		return "", fmt.Errorf("lettuce: %w", &gofs.PathError{Op: "readlink", Path: name, Err: err})
+	}
+
+	if !fe.IsSymlink() {
+		return "", fmt.Errorf("lettuce: %w", &gofs.PathError{Op: "readlink", Path: name, Err: gofs.ErrInvalid})
+	}
+	return fe.SymlinkTarget(), nil
+}
+
+// Readlink is an alias for ReadLink provided for parity with os.Readlink.
+func (l *Lettuce) Readlink(name string) (string, error) {
+	return l.ReadLink(name)
+}
+
+// Symlink creates newname as a symbolic link to oldname.
+//
+// Relative values for oldname are resolved against the directory containing newname, and absolute values are resolved
+// against the root of the file system.
+func (l *Lettuce) Symlink(oldname string, newname string) error {
+	return l.SymlinkContext(context.Background(), oldname, newname)
+}
+
+// SymlinkContext is like Symlink, but uses the provided context.Context for the operation.
+func (l *Lettuce) SymlinkContext(ctx context.Context, oldname string, newname string) error {
+	log.Debug("[lettuce] symlink",
+		log.String("old_name", oldname),
+		log.String("new_name", newname),
+	)
+
+	if err := symlink(ctx, l, oldname, newname); err != nil {
+		return fmt.Errorf("lettuce: %w", &goos.LinkError{Op: "symlink", Old: oldname, New: newname, Err: err})
+	}
+	return nil
+}
+
+func symlink(ctx context.Context, let *Lettuce, oldname string, newname string) error {
+	if oldname = strings.TrimSpace(oldname); oldname == "" {
+		return gofs.ErrInvalid
+	}
+
+	n, err := fs.CleanPath(let, newname)
+	if err != nil {
+		return err
+	}
+
+	if fs.EndsWithDot(let, n) {
+		return gofs.ErrInvalid
+	}
+
+	if _, err := let.cluster.Filer().Symlink(ctx, oldname, lpath(let, n)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// lstat returns the filer.Entry for the provided name without following symbolic links.
+func lstat(ctx context.Context, let *Lettuce, name string) (*filer.Entry, error) {
+	name, err := fs.CleanPath(let, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if name == "." {
+		return let.entry, nil
+	}
+	return lookup(ctx, let, lpath(let, name))
+}
+
+// stat returns the filer.Entry for the provided name following symbolic links.
+func stat(ctx context.Context, let *Lettuce, name string) (*filer.Entry, error) {
+	e, _, err := evalSymlinks(ctx, let, name)
+	return e, err
+}
+
+// evalSymlinks returns the filer.Entry for the provided name following symbolic links, along with the path relative to
+// the root of the file system the name resolved to.
+//
+// If the resolved entry does not exist, the returned path is the location that the name would resolve to, which can
+// be used for creating an entry through a dangling symbolic link.
+func evalSymlinks(ctx context.Context, let *Lettuce, name string) (*filer.Entry, string, error) {
+	name, err := fs.CleanPath(let, name)
+	if err != nil {
+		return nil, name, err
+	}
+
+	p := lpath(let, name)
+	if name == "." {
+		return let.entry, p, nil
+	}
+
+	for links := 0; ; links++ {
+		if links > symlinksMax {
+			return nil, p, ErrSymlinkLoop
+		}
+
+		e, err := lookup(ctx, let, p)
+		if err != nil {
+			if !errors.Is(err, gofs.ErrNotExist) {
+				return nil, p, err
+			}
+
+			// The entry may not exist because one of its parent directories is a symbolic link.
+			rp, ok, perr := resolveParent(ctx, let, p)
+			if perr != nil {
+				return nil, p, perr
+			}
+
+			if !ok {
+				return nil, p, err
+			}
+			p = rp
+			continue
+		}
+
+		if !e.IsSymlink() {
+			return e, p, nil
+		}
+
+		log.Trace("[lettuce] following symlink",
+			log.String("path", p),
+			log.String("target", e.SymlinkTarget()))
+
+		p = linkTarget(p, e.SymlinkTarget())
+	}
+}
+
+// resolveParent searches the parent directories of p, starting from the closest, for a symbolic link. If one is found,
+// the path with the symbolic link replaced by its target is returned.
+func resolveParent(ctx context.Context, let *Lettuce, p string) (string, bool, error) {
+	for dir := path.Dir(p); dir != "."; dir = path.Dir(dir) {
+		e, err := lookup(ctx, let, dir)
+		if err != nil {
+			if errors.Is(err, gofs.ErrNotExist) {
+				continue
+			}
+			return p, false, err
+		}
+
+		if !e.IsSymlink() {
+			if !e.IsDir() {
+				return p, false, fs.ErrNotDir
+			}
+			return p, false, nil
+		}
+		return path.Join(linkTarget(dir, e.SymlinkTarget()), strings.TrimPrefix(p, dir+"/")), true, nil
+	}
+	return p, false, nil
+}
+
+// lookup returns the filer.Entry for p, where p is relative to the root of the file system.
+func lookup(ctx context.Context, let *Lettuce, p string) (*filer.Entry, error) {
+	if p == "." {
+		return let.cluster.Filer().Root().Entry(), nil
+	}
+	return let.cluster.Filer().Stat(ctx, p)
+}
+
+// linkTarget returns the path relative to the root of the file system for the target of the symbolic link located at
+// name. Targets that would escape the root of the file system are clamped to it.
+func linkTarget(name string, target string) string {
+	if !path.IsAbs(target) {
+		target = path.Join(path.Dir(name), target)
+	}
+
+	if p := strings.TrimPrefix(path.Join("/", target), "/"); p != "" {
+		return p
+	}
+	return "."
+}
+
+// lpath returns the path for name relative to the root of the file system.
+func lpath(let *Lettuce, name string) string {
+	return path.Join(fsPath(let, let.entry.Path()), name)
+}
+
+// rootOf returns a Lettuce for the root of the file system that let belongs to.
+func rootOf(let *Lettuce) *Lettuce {
+	return derive(let, let.cluster.Filer().Root().Entry())
+}
@@ -0,0 +1,33 @@
+package lettuce
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLinkTarget(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		want   string
+	}{
+		{name: "a/link", target: "file", want: "a/file"},
+		{name: "a/link", target: "b/file", want: "a/b/file"},
+		{name: "a/b/link", target: "../file", want: "a/file"},
+		{name: "a/link", target: "./b/../file", want: "a/file"},
+		{name: "link", target: "file", want: "file"},
+		{name: "a/link", target: "/b/file", want: "b/file"},
+		{name: "a/link", target: "/", want: "."},
+		{name: "a/link", target: "..", want: "."},
+		{name: "a/link", target: "../../../file", want: "file"},
+		{name: "a/link", target: "/../../file", want: "file"},
+		{name: "link", target: "..", want: "."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+" "+tt.target, func(t *testing.T) {
+			assert.Equal(t, tt.want, linkTarget(tt.name, tt.target))
+		})
+	}
+}
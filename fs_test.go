@@ -3,8 +3,10 @@
 package lettuce
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -15,6 +17,7 @@ import (
 	"github.com/transientvariable/log-go"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 
 	gofs "io/fs"
@@ -100,3 +103,56 @@ func (t *LettuceTestSuite) TestFS() {
 
 	assert.NoError(t.T(), fstest.TestFS(t.lettuce, t.filePaths...))
 }
+
+func TestSymlinks(t *testing.T) {
+	let, err := New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = let.Close() })
+
+	ctx := context.Background()
+	dir := storagePrefix + "/symlinks"
+	require.NoError(t, let.MkdirAllContext(ctx, dir, gofs.ModeDir|0755))
+	t.Cleanup(func() { _ = let.RemoveAllContext(ctx, dir) })
+
+	require.NoError(t, let.WriteFileContext(ctx, dir+"/file", []byte("symlinks"), modeCreate))
+
+	// Each link in the chain links to the next, and the last link targets the file.
+	chain := make([]string, symlinksMax+1)
+	for i := range chain {
+		chain[i] = fmt.Sprintf("%s/chain%02d", dir, i)
+	}
+	for i, name := range chain {
+		target := "file"
+		if i < len(chain)-1 {
+			target = path.Base(chain[i+1])
+		}
+		require.NoError(t, let.SymlinkContext(ctx, target, name))
+	}
+
+	require.NoError(t, let.SymlinkContext(ctx, "loop_b", dir+"/loop_a"))
+	require.NoError(t, let.SymlinkContext(ctx, "loop_a", dir+"/loop_b"))
+	require.NoError(t, let.SymlinkContext(ctx, "../../../../..", dir+"/root"))
+
+	tests := []struct {
+		name string
+		want string
+		err  error
+	}{
+		{name: chain[1], want: "file"},
+		{name: chain[0], err: ErrSymlinkLoop},
+		{name: dir + "/loop_a", err: ErrSymlinkLoop},
+		{name: dir + "/root", want: "."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fi, err := let.StatContext(ctx, tt.name)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, fi.Name())
+		})
+	}
+}
@@ -0,0 +1,256 @@
+package lettuce
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/transientvariable/fs-go"
+	"github.com/transientvariable/lettuce/cluster/filer"
+	"github.com/transientvariable/log-go"
+
+	gofs "io/fs"
+)
+
+// Walk walks the file tree rooted at root, calling fn for each file or directory in the tree, including root.
+//
+// Unlike fs.WalkDir, the tree is retrieved from the filer in breadth-first order using a single request, so files are
+// not visited in lexical order. Files with one of the excluded paths, which are relative to root, are skipped along
+// with their descendants, so excluding "cache" does not skip "cache2". The behavior of fn is otherwise the same as for
+// fs.WalkDir, including the handling of fs.SkipDir and fs.SkipAll.
+func (l *Lettuce) Walk(root string, fn gofs.WalkDirFunc, excluded ...string) error {
+	return l.WalkContext(context.Background(), root, fn, excluded...)
+}
+
+// WalkContext is like Walk, but uses the provided context.Context for the operation.
+func (l *Lettuce) WalkContext(ctx context.Context, root string, fn gofs.WalkDirFunc, excluded ...string) error {
+	log.Debug("[lettuce] walk", log.String("root", root), log.Any("excluded", excluded))
+
+	if err := walk(ctx, l, root, fn, excluded...); err != nil {
+		var pathErr *gofs.PathError
+		if !errors.As(err, &pathErr) {
+			return fmt.Errorf("lettuce: %w", &gofs.PathError{Op: "walk", Path: root, Err: err})
+		}
+		return err
+	}
+	return nil
+}
+
+func walk(ctx context.Context, let *Lettuce, root string, fn gofs.WalkDirFunc, excluded ...string) error {
+	dir, err := stat(ctx, let, root)
+	if err != nil {
+		return skip(fn(root, nil, err))
+	}
+
+	de, err := FSEntry(let, dir)
+	if err != nil {
+		return skip(fn(root, nil, err))
+	}
+
+	if err := fn(root, de, nil); err != nil || !dir.IsDir() {
+		return skip(err)
+	}
+
+	base := dir.Path().String()
+	prefixes := make([]string, len(excluded))
+	for i, e := range excluded {
+		prefixes[i] = path.Join(fsPath(let, dir.Path()), e)
+	}
+
+	// Directories skipped by fn are tracked by their path, since the filer continues to send their descendants.
+	skipped := make(map[string]bool)
+	err = let.cluster.Filer().Traverse(ctx, base, func(e *filer.Entry) error {
+		p := e.Path().String()
+		for d := path.Dir(p); d != base && len(d) > len(base); d = path.Dir(d) {
+			if skipped[d] {
+				return nil
+			}
+		}
+
+		name := path.Join(root, strings.TrimPrefix(p, base+"/"))
+		de, err := FSEntry(let, e)
+		if err != nil {
+			return fn(name, nil, err)
+		}
+
+		if err := fn(name, de, nil); err != nil {
+			if !errors.Is(err, gofs.SkipDir) {
+				return err
+			}
+
+			// Returning fs.SkipDir for a file skips the remaining files in the containing directory.
+			if e.IsDir() {
+				skipped[p] = true
+			} else {
+				skipped[path.Dir(p)] = true
+			}
+		}
+		return nil
+	}, prefixes...)
+	return skip(err)
+}
+
+// skip returns nil if err is fs.SkipDir or fs.SkipAll, otherwise err is returned.
+func skip(err error) error {
+	if errors.Is(err, gofs.SkipDir) || errors.Is(err, gofs.SkipAll) {
+		return nil
+	}
+	return err
+}
+
+// glob returns the names of all files matching pattern, where pattern has the syntax of path.Match with the addition
+// of "**", which matches zero or more directories when used as a complete path element.
+//
+// Only the directories that can contain matching files are listed, one level at a time. Once a "**" element is
+// reached, the remaining elements are matched by walking the subtree of each directory matched so far.
+func glob(ctx context.Context, let *Lettuce, pattern string) ([]string, error) {
+	elems := strings.Split(path.Clean(pattern), "/")
+	for _, e := range elems {
+		if _, err := path.Match(e, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	matches := []string{"."}
+	for i, e := range elems {
+		if e == "**" {
+			return globWalk(ctx, let, matches, elems[i:])
+		}
+
+		var next []string
+		for _, dir := range matches {
+			names, err := globDir(ctx, let, dir, e)
+			if err != nil {
+				return nil, err
+			}
+			next = append(next, names...)
+		}
+
+		if len(next) == 0 {
+			return nil, nil
+		}
+		matches = next
+	}
+
+	slices.Sort(matches)
+	return matches, nil
+}
+
+// globDir returns the names of the files in the directory dir that match the pattern element elem. If dir does not
+// exist or is not a directory, no names are returned.
+func globDir(ctx context.Context, let *Lettuce, dir string, elem string) ([]string, error) {
+	if !strings.ContainsAny(elem, `*?[\`) {
+		name := path.Join(dir, elem)
+		if _, err := lstat(ctx, let, name); err != nil {
+			if errors.Is(err, gofs.ErrNotExist) || errors.Is(err, fs.ErrNotDir) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		return []string{name}, nil
+	}
+
+	entries, err := let.ReadDirContext(ctx, dir)
+	if err != nil {
+		if errors.Is(err, gofs.ErrNotExist) || errors.Is(err, fs.ErrNotDir) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var names []string
+	for _, e := range entries {
+		if ok, _ := path.Match(elem, e.Name()); ok {
+			names = append(names, path.Join(dir, e.Name()))
+		}
+	}
+	return names, nil
+}
+
+// globWalk returns the names of the files within each of the directories dirs that match the pattern elements, which
+// are relative to the directory and start with "**".
+func globWalk(ctx context.Context, let *Lettuce, dirs []string, pattern []string) ([]string, error) {
+	var matches []string
+	for _, root := range dirs {
+		err := walk(ctx, let, root, func(p string, d gofs.DirEntry, err error) error {
+			if err != nil {
+				if errors.Is(err, gofs.ErrNotExist) || errors.Is(err, fs.ErrNotDir) {
+					return nil
+				}
+				return err
+			}
+
+			rel := strings.TrimPrefix(p, root+"/")
+			if root == "." {
+				rel = p
+			}
+
+			var names []string
+			if p != root {
+				names = strings.Split(rel, "/")
+			}
+
+			if p != "." && globMatch(pattern, names) {
+				matches = append(matches, p)
+			}
+
+			if d.IsDir() && !globPrefix(pattern, names) {
+				return gofs.SkipDir
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	slices.Sort(matches)
+	return matches, nil
+}
+
+// globMatch returns whether the path elements in names match the pattern elements.
+func globMatch(pattern []string, names []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := range len(names) + 1 {
+				if globMatch(pattern[1:], names[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(names) == 0 {
+			return false
+		}
+
+		if ok, _ := path.Match(pattern[0], names[0]); !ok {
+			return false
+		}
+		pattern, names = pattern[1:], names[1:]
+	}
+	return len(names) == 0
+}
+
+// globPrefix returns whether the descendants of the directory with the path elements in names can match the pattern
+// elements.
+func globPrefix(pattern []string, names []string) bool {
+	for _, n := range names {
+		if len(pattern) == 0 {
+			return false
+		}
+
+		if pattern[0] == "**" {
+			return true
+		}
+
+		if ok, _ := path.Match(pattern[0], n); !ok {
+			return false
+		}
+		pattern = pattern[1:]
+	}
+	return len(pattern) > 0
+}
@@ -0,0 +1,72 @@
+package lettuce
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGlobMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{pattern: "a/b", name: "a/b", want: true},
+		{pattern: "a/b", name: "a/c"},
+		{pattern: "a/*", name: "a/b", want: true},
+		{pattern: "a/*", name: "a/b/c"},
+		{pattern: "a/*.gz", name: "a/b.gz", want: true},
+		{pattern: "a/?", name: "a/bc"},
+		{pattern: "a/[bc]", name: "a/c", want: true},
+		{pattern: "**", name: "a", want: true},
+		{pattern: "**", name: "a/b/c", want: true},
+		{pattern: "a/**", name: "a", want: true},
+		{pattern: "a/**", name: "a/b/c", want: true},
+		{pattern: "a/**", name: "b/c"},
+		{pattern: "a/**/c", name: "a/c", want: true},
+		{pattern: "a/**/c", name: "a/b/d/c", want: true},
+		{pattern: "a/**/c", name: "a/b/c/d"},
+		{pattern: "**/*.gz", name: "a/b/c.gz", want: true},
+		{pattern: "**/*.gz", name: "c.gz", want: true},
+		{pattern: "**/*.gz", name: "a/b/c.txt"},
+		{pattern: "a/**/b/**/c", name: "a/x/b/y/z/c", want: true},
+		{pattern: "a/**/b/**/c", name: "a/x/y/z/c"},
+		{pattern: "a/b**", name: "a/bcd", want: true},
+		{pattern: "a/b**", name: "a/b/c"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, globMatch(strings.Split(tt.pattern, "/"), strings.Split(tt.name, "/")))
+		})
+	}
+}
+
+func TestGlobPrefix(t *testing.T) {
+	tests := []struct {
+		pattern string
+		dir     string
+		want    bool
+	}{
+		{pattern: "a/b/c", dir: "a", want: true},
+		{pattern: "a/b/c", dir: "a/b", want: true},
+		{pattern: "a/b/c", dir: "a/b/c"},
+		{pattern: "a/b/c", dir: "x"},
+		{pattern: "a/*/c", dir: "a/x", want: true},
+		{pattern: "a/*/c", dir: "a/x/c"},
+		{pattern: "a/*", dir: "a/x"},
+		{pattern: "a/**", dir: "a", want: true},
+		{pattern: "a/**", dir: "a/x/y", want: true},
+		{pattern: "a/**/c", dir: "a/x/y/z", want: true},
+		{pattern: "a/**/c", dir: "b"},
+		{pattern: "**", dir: "x/y", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.dir, func(t *testing.T) {
+			assert.Equal(t, tt.want, globPrefix(strings.Split(tt.pattern, "/"), strings.Split(tt.dir, "/")))
+		})
+	}
+}
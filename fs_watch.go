@@ -0,0 +1,245 @@
+package lettuce
+
+import (
+	"context"
+	"errors"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/transientvariable/fs-go"
+	"github.com/transientvariable/lettuce/pb/filer_pb"
+	"github.com/transientvariable/log-go"
+)
+
+// EventType describes the change to a file or directory reported by an Event.
+type EventType int
+
+// Enumeration of supported event types.
+const (
+	EventCreate EventType = iota + 1
+	EventUpdate
+	EventDelete
+	EventRename
+)
+
+// String returns a string representation of the EventType.
+func (t EventType) String() string {
+	switch t {
+	case EventCreate:
+		return "create"
+	case EventUpdate:
+		return "update"
+	case EventDelete:
+		return "delete"
+	case EventRename:
+		return "rename"
+	default:
+		return "unknown"
+	}
+}
+
+// Event describes a change to a file or directory received using Lettuce.Watch.
+type Event struct {
+	// Type is the type of change.
+	Type EventType
+
+	// Entry describes the file after the change, which is nil for EventDelete.
+	Entry *fs.Entry
+
+	// Err is set if the entries describing the change could not be converted, in which case Entry and OldEntry may be
+	// nil even though the Type indicates otherwise.
+	Err error
+
+	// OldEntry describes the file before the change, which is nil for EventCreate.
+	OldEntry *fs.Entry
+
+	// TsNs is the time of the change in nanoseconds since the Unix epoch, which can be used as a checkpoint for
+	// resuming a watch after the Event (see Lettuce.WatchSince).
+	TsNs int64
+}
+
+// Watch returns a channel that receives an Event for each change to files with a path that starts with one of the
+// provided prefixes, which are relative to the Lettuce. If no prefixes are provided, changes to all files within the
+// Lettuce are received. Prefixes are matched as strings, so the prefix "logs" also matches changes to "logs.old".
+//
+// Only changes made after Watch is called are received. The channel is closed once the context is done. If the
+// connection to the filer fails, Watch reconnects with exponential backoff and resumes after the last received Event.
+func (l *Lettuce) Watch(ctx context.Context, prefixes ...string) <-chan Event {
+	return l.WatchSince(ctx, time.Now().UnixNano(), prefixes...)
+}
+
+// WatchSince is like Watch, but only receives changes made after sinceNs, which is the time in nanoseconds since the
+// Unix epoch. The value of Event.TsNs for the last processed Event can be used for resuming a previous watch.
+//
+// Distinct changes may share the same time. After reconnecting, changes at the time of the last received Event that
+// were already received are skipped, while the others are received.
+func (l *Lettuce) WatchSince(ctx context.Context, sinceNs int64, prefixes ...string) <-chan Event {
+	log.Debug("[lettuce] watch", log.Any("prefixes", prefixes), log.Int64("since_ns", sinceNs))
+
+	if len(prefixes) == 0 {
+		prefixes = []string{"."}
+	}
+
+	paths := make([]string, len(prefixes))
+	for i, p := range prefixes {
+		paths[i] = lpath(l, p)
+	}
+
+	events := make(chan Event)
+	go func() {
+		defer close(events)
+
+		b := backoff.NewExponentialBackOff()
+		b.MaxElapsedTime = 0
+
+		c := &watchCursor{sinceNs: sinceNs}
+		for {
+			err := l.cluster.Filer().Subscribe(ctx, c.from(), func(resp *filer_pb.SubscribeMetadataResponse) error {
+				b.Reset()
+
+				if !c.next(resp) {
+					return nil
+				}
+
+				e, ok := newEvent(l, resp)
+				if e.Err != nil {
+					log.Error("[lettuce] watch: could not convert event", log.Err(e.Err))
+				}
+
+				if ok {
+					select {
+					case events <- e:
+					case <-ctx.Done():
+						return ctx.Err()
+					}
+				}
+				return nil
+			}, paths...)
+
+			if ctx.Err() != nil {
+				return
+			}
+
+			d := b.NextBackOff()
+			log.Warn("[lettuce] watch: subscription ended, reconnecting",
+				log.Err(err),
+				log.Int64("since_ns", c.sinceNs),
+				log.String("backoff", d.String()))
+
+			select {
+			case <-time.After(d):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return events
+}
+
+// newEvent creates an Event from the provided filer_pb.SubscribeMetadataResponse, and returns whether the response
+// describes a change to an entry. Errors converting the entries for the change are reported using Event.Err.
+func newEvent(let *Lettuce, resp *filer_pb.SubscribeMetadataResponse) (Event, bool) {
+	t, ok := eventType(resp)
+	if !ok {
+		return Event{}, false
+	}
+
+	n := resp.GetEventNotification()
+	e := Event{Type: t, TsNs: resp.GetTsNs()}
+
+	var err error
+	if n.GetOldEntry() != nil {
+		if e.OldEntry, err = eventEntry(let, resp.GetDirectory(), n.GetOldEntry()); err != nil {
+			e.Err = err
+		}
+	}
+
+	if n.GetNewEntry() != nil {
+		if e.Entry, err = eventEntry(let, eventDir(resp), n.GetNewEntry()); err != nil {
+			e.Err = errors.Join(e.Err, err)
+		}
+	}
+	return e, true
+}
+
+// eventType returns the EventType for the change described by the provided filer_pb.SubscribeMetadataResponse, and
+// whether the response describes a change to an entry.
+func eventType(resp *filer_pb.SubscribeMetadataResponse) (EventType, bool) {
+	n := resp.GetEventNotification()
+	switch o, ne := n.GetOldEntry(), n.GetNewEntry(); {
+	case o == nil && ne == nil:
+		return 0, false
+	case o == nil:
+		return EventCreate, true
+	case ne == nil:
+		return EventDelete, true
+	case o.GetName() != ne.GetName() || (n.GetNewParentPath() != "" && n.GetNewParentPath() != resp.GetDirectory()):
+		return EventRename, true
+	default:
+		return EventUpdate, true
+	}
+}
+
+// eventDir returns the directory containing the entry after the change described by the provided
+// filer_pb.SubscribeMetadataResponse.
+func eventDir(resp *filer_pb.SubscribeMetadataResponse) string {
+	if dir := resp.GetEventNotification().GetNewParentPath(); dir != "" {
+		return dir
+	}
+	return resp.GetDirectory()
+}
+
+// eventKey returns the key identifying the change described by the provided filer_pb.SubscribeMetadataResponse among
+// the changes made at the same time.
+func eventKey(resp *filer_pb.SubscribeMetadataResponse) string {
+	n := resp.GetEventNotification()
+	return strings.Join([]string{
+		resp.GetDirectory(),
+		n.GetOldEntry().GetName(),
+		eventDir(resp),
+		n.GetNewEntry().GetName(),
+	}, "\x00")
+}
+
+// eventEntry converts the protobuf entry located in the directory dir, which is a full path, to an fs.Entry.
+func eventEntry(let *Lettuce, dir string, pbEntry *filer_pb.Entry) (*fs.Entry, error) {
+	e, err := let.cluster.Filer().NewEntry(path.Clean(dir), pbEntry)
+	if err != nil {
+		return nil, err
+	}
+	return FSEntry(let, e)
+}
+
+// watchCursor tracks the position of a watch within the metadata events received from the filer, so that events are
+// not received twice when a subscription is resumed.
+type watchCursor struct {
+	// seen contains the keys (see eventKey) of the events received at sinceNs, which is nil until an event is
+	// received, since it is not known which of the changes at the time provided by the caller were processed.
+	seen    map[string]bool
+	sinceNs int64
+}
+
+// from returns the time in nanoseconds since the Unix epoch to resume a subscription from. Subscriptions resume just
+// before the last received event, so that other changes at the same time are received again.
+func (c *watchCursor) from() int64 {
+	if c.seen != nil {
+		return c.sinceNs - 1
+	}
+	return c.sinceNs
+}
+
+// next advances the watchCursor to the change described by the provided filer_pb.SubscribeMetadataResponse, and
+// returns whether the change has not been received before.
+func (c *watchCursor) next(resp *filer_pb.SubscribeMetadataResponse) bool {
+	key := eventKey(resp)
+	switch ts := resp.GetTsNs(); {
+	case ts < c.sinceNs || (ts == c.sinceNs && (c.seen == nil || c.seen[key])):
+		return false
+	case ts > c.sinceNs:
+		c.sinceNs, c.seen = ts, make(map[string]bool)
+	}
+	c.seen[key] = true
+	return true
+}
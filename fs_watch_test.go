@@ -0,0 +1,132 @@
+package lettuce
+
+import (
+	"testing"
+
+	"github.com/transientvariable/lettuce/pb/filer_pb"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testEvent returns a filer_pb.SubscribeMetadataResponse for a change at tsNs to an entry in dir from the entry named
+// oldName to the entry named newName in newDir, where an empty name means that the entry does not exist.
+func testEvent(tsNs int64,
+	dir string,
+	oldName string,
+	newDir string,
+	newName string,
+) *filer_pb.SubscribeMetadataResponse {
+	n := &filer_pb.EventNotification{NewParentPath: newDir}
+	if oldName != "" {
+		n.OldEntry = &filer_pb.Entry{Name: oldName}
+	}
+
+	if newName != "" {
+		n.NewEntry = &filer_pb.Entry{Name: newName}
+	}
+	return &filer_pb.SubscribeMetadataResponse{Directory: dir, EventNotification: n, TsNs: tsNs}
+}
+
+func TestEventType(t *testing.T) {
+	tests := []struct {
+		name string
+		resp *filer_pb.SubscribeMetadataResponse
+		want EventType
+	}{
+		{name: "create", resp: testEvent(1, "/a", "", "/a", "f"), want: EventCreate},
+		{name: "create without new parent", resp: testEvent(1, "/a", "", "", "f"), want: EventCreate},
+		{name: "update", resp: testEvent(1, "/a", "f", "/a", "f"), want: EventUpdate},
+		{name: "update without new parent", resp: testEvent(1, "/a", "f", "", "f"), want: EventUpdate},
+		{name: "delete", resp: testEvent(1, "/a", "f", "", ""), want: EventDelete},
+		{name: "rename", resp: testEvent(1, "/a", "f", "/a", "g"), want: EventRename},
+		{name: "move", resp: testEvent(1, "/a", "f", "/b", "f"), want: EventRename},
+		{name: "no entries", resp: testEvent(1, "/a", "", "", "")},
+		{name: "no notification", resp: &filer_pb.SubscribeMetadataResponse{Directory: "/a", TsNs: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := eventType(tt.resp)
+			assert.Equal(t, tt.want != 0, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEventKey(t *testing.T) {
+	events := []*filer_pb.SubscribeMetadataResponse{
+		testEvent(1, "/a", "", "/a", "f"),
+		testEvent(1, "/a", "f", "/a", "f"),
+		testEvent(1, "/a", "f", "", ""),
+		testEvent(1, "/a", "f", "/a", "g"),
+		testEvent(1, "/a", "f", "/b", "f"),
+		testEvent(1, "/a", "", "/a", "g"),
+		testEvent(1, "/a/f", "", "", ""),
+	}
+
+	keys := make(map[string]int)
+	for i, e := range events {
+		if j, ok := keys[eventKey(e)]; ok {
+			t.Errorf("events %d and %d have the same key", j, i)
+		}
+		keys[eventKey(e)] = i
+	}
+
+	// Events without a new parent are located in the directory of the change.
+	assert.Equal(t, eventKey(testEvent(1, "/a", "f", "/a", "f")), eventKey(testEvent(2, "/a", "f", "", "f")))
+}
+
+func TestWatchCursor(t *testing.T) {
+	created := testEvent(20, "/a", "", "/a", "f")
+	updated := testEvent(20, "/a", "f", "/a", "f")
+	removed := testEvent(30, "/a", "f", "", "")
+
+	tests := []struct {
+		name     string
+		events   []*filer_pb.SubscribeMetadataResponse
+		want     []bool
+		wantFrom int64
+	}{
+		{name: "none", wantFrom: 10},
+		{
+			name:     "in order",
+			events:   []*filer_pb.SubscribeMetadataResponse{created, updated, removed},
+			want:     []bool{true, true, true},
+			wantFrom: 29,
+		},
+		{
+			name:     "before start",
+			events:   []*filer_pb.SubscribeMetadataResponse{testEvent(5, "/a", "", "/a", "f")},
+			want:     []bool{false},
+			wantFrom: 10,
+		},
+		{
+			name:     "at start",
+			events:   []*filer_pb.SubscribeMetadataResponse{testEvent(10, "/a", "", "/a", "f")},
+			want:     []bool{false},
+			wantFrom: 10,
+		},
+		{
+			name:     "resumed at same time",
+			events:   []*filer_pb.SubscribeMetadataResponse{created, created, updated, updated},
+			want:     []bool{true, false, true, false},
+			wantFrom: 19,
+		},
+		{
+			name:     "resumed after later event",
+			events:   []*filer_pb.SubscribeMetadataResponse{created, removed, created, updated, removed},
+			want:     []bool{true, true, false, false, false},
+			wantFrom: 29,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &watchCursor{sinceNs: 10}
+			for i, e := range tt.events {
+				assert.Equal(t, tt.want[i], c.next(e), "event %d", i)
+			}
+			assert.Equal(t, tt.wantFrom, c.from())
+		})
+	}
+}
@@ -2,6 +2,7 @@ package lettuce
 
 import (
 	"context"
+	"encoding/xml"
 	"errors"
 	"fmt"
 	"os"
@@ -9,17 +10,31 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/transientvariable/fs-go"
+	"github.com/transientvariable/lettuce/cluster/filer"
 	"github.com/transientvariable/log-go"
 
 	"golang.org/x/net/webdav"
 
+	json "github.com/json-iterator/go"
 	gofs "io/fs"
+	gohttp "net/http"
+)
+
+const (
+	// webDAVPropKeyPrefix defines the prefix for the keys of the extended attributes used for storing WebDAV dead
+	// properties, which are followed by the name of the property in Clark notation (e.g. {DAV:}displayname).
+	webDAVPropKeyPrefix = "webdav-prop-"
 )
 
 var (
-	_ webdav.FileSystem = (*WebDAV)(nil)
+	_ webdav.ContentTyper    = (*webDAVFileInfo)(nil)
+	_ webdav.DeadPropsHolder = (*webDAVFile)(nil)
+	_ webdav.ETager          = (*webDAVFileInfo)(nil)
+	_ webdav.File            = (*webDAVFile)(nil)
+	_ webdav.FileSystem      = (*WebDAV)(nil)
 )
 
 // WebDAV an implementation of the webdav.FileSystem using SeaweedFS for the storage backend.
@@ -58,6 +73,28 @@ func (w *WebDAV) Close() error {
 	return fmt.Errorf("lettuce_webdav: %w", gofs.ErrClosed)
 }
 
+// Chmod changes the mode of the named file. See Lettuce.Chmod for details.
+func (w *WebDAV) Chmod(ctx context.Context, name string, mode os.FileMode) error {
+	log.Debug("[lettuce:webdav] chmod", log.String("name", name), log.String("mode", mode.String()))
+
+	return w.let.ChmodContext(ctx, resolve(name), mode)
+}
+
+// Chown changes the numeric uid and gid of the named file. See Lettuce.Chown for details.
+func (w *WebDAV) Chown(ctx context.Context, name string, uid int, gid int) error {
+	log.Debug("[lettuce:webdav] chown", log.String("name", name), log.Int("uid", uid), log.Int("gid", gid))
+
+	return w.let.ChownContext(ctx, resolve(name), uid, gid)
+}
+
+// Chtimes changes the modification time of the named file, e.g. to apply a modification time supplied by a client
+// alongside an upload. See Lettuce.Chtimes for details.
+func (w *WebDAV) Chtimes(ctx context.Context, name string, atime time.Time, mtime time.Time) error {
+	log.Debug("[lettuce:webdav] chtimes", log.String("name", name), log.Time("mtime", mtime))
+
+	return w.let.ChtimesContext(ctx, resolve(name), atime, mtime)
+}
+
 func (w *WebDAV) Mkdir(ctx context.Context, name string, mode os.FileMode) error {
 	name = resolve(name)
 
@@ -95,7 +132,7 @@ func (w *WebDAV) OpenFile(ctx context.Context, name string, flag int, mode os.Fi
 		}
 		return nil, err
 	}
-	return f, nil
+	return &webDAVFile{File: f, w: w}, nil
 }
 
 func (w *WebDAV) Rename(ctx context.Context, oldName string, newName string) error {
@@ -124,10 +161,11 @@ func (w *WebDAV) Stat(ctx context.Context, name string) (os.FileInfo, error) {
 	e, err := w.stat(ctx, resolve(name), "stat")
 	if err != nil {
 		if errors.Is(err, gofs.ErrNotExist) {
-			return e, gofs.ErrNotExist
+			return nil, gofs.ErrNotExist
 		}
+		return nil, err
 	}
-	return e, err
+	return e, nil
 }
 
 func (w *WebDAV) isDir(ctx context.Context, name string, op string) bool {
@@ -138,7 +176,7 @@ func (w *WebDAV) isDir(ctx context.Context, name string, op string) bool {
 	return fi.IsDir()
 }
 
-func (w *WebDAV) stat(ctx context.Context, name string, op string) (*fs.Entry, error) {
+func (w *WebDAV) stat(ctx context.Context, name string, op string) (*webDAVFileInfo, error) {
 	log.Debug("[lettuce:webdav] stat", log.String("name", name), log.String("op", op))
 
 	fe, err := stat(ctx, w.let, name)
@@ -146,11 +184,163 @@ func (w *WebDAV) stat(ctx context.Context, name string, op string) (*fs.Entry, e
 		return nil, err
 	}
 
-	e, err := FSEntry(w.let, fe)
+	e, err := newFileInfo(w.let, fe)
 	if err != nil {
 		return nil, err
 	}
-	return e, nil
+	return &webDAVFileInfo{fileInfo: e}, nil
+}
+
+// webDAVFile wraps a File with behavior specific to the webdav.File interface.
+type webDAVFile struct {
+	*File
+	w *WebDAV
+}
+
+// DeadProps returns the dead properties for the webDAVFile, which are stored as extended attributes of its entry.
+func (f *webDAVFile) DeadProps() (map[xml.Name]webdav.Property, error) {
+	if err := f.checkOpen("deadProps"); err != nil {
+		return nil, err
+	}
+
+	props := make(map[xml.Name]webdav.Property)
+	for _, k := range f.entry.ExtendedKeys() {
+		n, ok := webDAVPropName(k)
+		if !ok {
+			continue
+		}
+
+		v, _ := f.entry.Extended(k)
+		var p webdav.Property
+		if err := json.Unmarshal(v, &p); err != nil {
+			log.Warn("[lettuce:webdav] skipping invalid dead property", log.String("key", k), log.Err(err))
+			continue
+		}
+		p.XMLName = n
+		props[n] = p
+	}
+	return props, nil
+}
+
+// Patch applies the provided changes to the dead properties of the webDAVFile. Changes are applied together, so
+// either all changes succeed or none do.
+func (f *webDAVFile) Patch(patches []webdav.Proppatch) ([]webdav.Propstat, error) {
+	log.Debug("[lettuce:webdav] patch", log.String("name", f.entry.Name()), log.Int("patches", len(patches)))
+
+	stat := webdav.Propstat{Status: gohttp.StatusOK}
+	err := f.chattr("patch", func(e *filer.Entry) error {
+		for _, patch := range patches {
+			for _, p := range patch.Props {
+				stat.Props = append(stat.Props, webdav.Property{XMLName: p.XMLName})
+
+				k := webDAVPropKey(p.XMLName)
+				if patch.Remove {
+					e.DeleteExtended(k)
+					continue
+				}
+
+				v, err := json.Marshal(p)
+				if err != nil {
+					return err
+				}
+				e.SetExtended(k, v)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return []webdav.Propstat{stat}, nil
+}
+
+// Stat returns the fs.FileInfo describing the webDAVFile, which implements the webdav.ETager and webdav.ContentTyper
+// interfaces.
+func (f *webDAVFile) Stat() (gofs.FileInfo, error) {
+	fi, err := f.File.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	if e, ok := fi.(*fileInfo); ok {
+		return &webDAVFileInfo{fileInfo: e}, nil
+	}
+	return fi, nil
+}
+
+// Readdir returns the directory entries for the webDAVFile.
+//
+// WebDAV has no notion of symbolic links, so entries representing symbolic links are reported using the fs.FileInfo
+// of their target. Symbolic links that cannot be resolved are omitted.
+func (f *webDAVFile) Readdir(count int) ([]gofs.FileInfo, error) {
+	entries, err := f.File.Readdir(count)
+
+	infos := make([]gofs.FileInfo, 0, len(entries))
+	for _, e := range entries {
+		if e.Mode()&gofs.ModeSymlink == 0 {
+			infos = append(infos, e)
+			continue
+		}
+
+		link := e.(*fs.Entry)
+		fi, serr := f.w.stat(f.ctx, link.Path(), "readdir")
+		if serr != nil {
+			log.Trace("[lettuce:webdav] skipping unresolvable symlink",
+				log.String("name", link.Path()),
+				log.Err(serr))
+			continue
+		}
+
+		if serr := fi.SetPath(link.Path()); serr != nil {
+			return infos, serr
+		}
+		infos = append(infos, fi)
+	}
+	return infos, err
+}
+
+// webDAVFileInfo extends a fileInfo with the webdav.ETager and webdav.ContentTyper interfaces, which use the metadata
+// stored by the filer rather than reading the content of the file.
+type webDAVFileInfo struct {
+	*fileInfo
+}
+
+// ContentType returns the MIME type stored for the file. The error webdav.ErrNotImplemented is returned if the MIME
+// type is not known, in which case it is detected from the file name or content.
+func (fi *webDAVFileInfo) ContentType(context.Context) (string, error) {
+	if fi.IsDir() || fi.filerEntry.Mime() == "" {
+		return "", webdav.ErrNotImplemented
+	}
+	return fi.filerEntry.Mime(), nil
+}
+
+// ETag returns the entity tag for the file computed from the digests of its content (see filer.Entry.ETag). The error
+// webdav.ErrNotImplemented is returned for directories.
+func (fi *webDAVFileInfo) ETag(context.Context) (string, error) {
+	if fi.IsDir() {
+		return "", webdav.ErrNotImplemented
+	}
+	return `"` + fi.filerEntry.ETag() + `"`, nil
+}
+
+// webDAVPropKey returns the key of the extended attribute used for storing the dead property with the provided name.
+func webDAVPropKey(n xml.Name) string {
+	return webDAVPropKeyPrefix + "{" + n.Space + "}" + n.Local
+}
+
+// webDAVPropName returns the name of the dead property stored using the provided extended attribute key, and whether
+// the key is for a dead property.
+func webDAVPropName(key string) (xml.Name, bool) {
+	k, ok := strings.CutPrefix(key, webDAVPropKeyPrefix+"{")
+	if !ok {
+		return xml.Name{}, false
+	}
+
+	space, local, ok := strings.Cut(k, "}")
+	if !ok || local == "" {
+		return xml.Name{}, false
+	}
+	return xml.Name{Space: space, Local: local}, true
 }
 
 func resolve(name string) string {
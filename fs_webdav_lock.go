@@ -0,0 +1,593 @@
+package lettuce
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/transientvariable/lettuce/cluster/filer"
+	"github.com/transientvariable/log-go"
+
+	"golang.org/x/net/webdav"
+
+	json "github.com/json-iterator/go"
+	gofs "io/fs"
+)
+
+const (
+	// webDAVLeaseTTL defines the lease for locks with an infinite timeout, which is renewed every third of the lease by
+	// the WebDAV server that created or refreshed the lock, so that the lock is removed if the server stops.
+	webDAVLeaseTTL = time.Minute
+
+	// webDAVLockKey defines the key of the extended attribute used for storing the details of a WebDAV lock.
+	webDAVLockKey = "webdav-lock"
+
+	// webDAVMutexTTL defines the time-to-live for the distributed lock used for serializing changes to WebDAV locks.
+	webDAVMutexTTL = 10 * time.Second
+
+	// webDAVMutexRetryInterval defines the interval between attempts to acquire the distributed lock used for
+	// serializing changes to WebDAV locks.
+	webDAVMutexRetryInterval = 50 * time.Millisecond
+)
+
+var (
+	_ webdav.LockSystem = (*WebDAVLockSystem)(nil)
+)
+
+// WebDAVLockSystem is an implementation of webdav.LockSystem that stores WebDAV locks in the filer, so that locks are
+// shared by every WebDAV server using the same directory for storing locks.
+//
+// Each lock is stored as an entry in the lock directory, and changes to locks are serialized using a distributed lock
+// (see filer.Lock). Expired locks are removed when locks are next changed.
+//
+// The distributed lock is a single mutex shared by every WebDAV server using the lock directory, and Confirm and Create
+// list every lock in the lock directory while holding it. A write request without an If header, for which
+// webdav.Handler creates and then unlocks a temporary lock, therefore takes the mutex twice and makes at least nine
+// filer requests, which limits the rate of writes across all of the WebDAV servers sharing the lock directory.
+//
+// Locks with an infinite timeout, which includes the temporary locks created by webdav.Handler for write requests
+// without an If header, are stored with a lease that is renewed in the background by the WebDAV server that created
+// them (see webDAVLeaseTTL), so that they do not outlive the server.
+type WebDAVLockSystem struct {
+	dir      string
+	holds    map[string]bool
+	leases   map[string]bool
+	let      *Lettuce
+	mutex    sync.Mutex
+	owner    string
+	renewing bool
+}
+
+// webDAVLock is the representation of a WebDAV lock stored in the filer.
+type webDAVLock struct {
+	Duration  time.Duration `json:"duration"`
+	ExpiresNs int64         `json:"expires_ns"`
+	HeldBy    string        `json:"held_by,omitempty"`
+	HeldNs    int64         `json:"held_ns,omitempty"`
+	OwnerXML  string        `json:"owner_xml,omitempty"`
+	Root      string        `json:"root"`
+	Token     string        `json:"token"`
+	ZeroDepth bool          `json:"zero_depth,omitempty"`
+}
+
+// NewWebDAVLockSystem creates a new webdav.LockSystem that stores locks in the named directory, which is created if
+// it does not exist. The directory should not be served by the webdav.FileSystem the locks are used with.
+func NewWebDAVLockSystem(let *Lettuce, dir string) (*WebDAVLockSystem, error) {
+	if let == nil {
+		return nil, errors.New("lettuce_webdav: lettuce backend is required")
+	}
+
+	l, err := mkdirAll(context.Background(), let, dir, gofs.ModeDir|0o700)
+	if err != nil {
+		return nil, fmt.Errorf("lettuce_webdav: %w", &gofs.PathError{Op: "newLockSystem", Path: dir, Err: err})
+	}
+
+	host, err := os.Hostname()
+	if err != nil {
+		host = "lettuce"
+	}
+	return &WebDAVLockSystem{
+		dir:    l.entry.Path().String(),
+		holds:  make(map[string]bool),
+		leases: make(map[string]bool),
+		let:    let,
+		owner:  fmt.Sprintf("%s:%d", host, os.Getpid()),
+	}, nil
+}
+
+// Confirm confirms that the caller can claim all of the locks specified by the given conditions for the resources
+// name0 and name1, either of which may be empty.
+//
+// The confirmed locks are held until the returned release function is called, so they cannot be confirmed, refreshed
+// or unlocked by other requests on any WebDAV server in the meantime. Holds are stored with a lease that is renewed by
+// the WebDAV server holding the locks (see webDAVLeaseTTL), so that they do not outlive the server.
+func (s *WebDAVLockSystem) Confirm(now time.Time,
+	name0 string,
+	name1 string,
+	conditions ...webdav.Condition,
+) (func(), error) {
+	log.Debug("[lettuce:webdav] confirm", log.String("name0", name0), log.String("name1", name1))
+
+	var tokens []string
+	err := s.serialize(func(ctx context.Context) error {
+		locks, err := s.locks(ctx, now)
+		if err != nil {
+			return err
+		}
+
+		var held []webDAVLock
+		for _, name := range []string{name0, name1} {
+			if name == "" {
+				continue
+			}
+
+			lk, ok := confirm(locks, now, webDAVLockPath(name), conditions...)
+			if !ok {
+				return webdav.ErrConfirmationFailed
+			}
+
+			if !slices.ContainsFunc(held, func(h webDAVLock) bool { return h.Token == lk.Token }) {
+				held = append(held, lk)
+			}
+		}
+
+		for _, lk := range held {
+			lk.HeldBy, lk.HeldNs = s.owner, now.Add(webDAVLeaseTTL).UnixNano()
+			if err := s.put(ctx, lk, false); err != nil {
+				return err
+			}
+			tokens = append(tokens, lk.Token)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, token := range tokens {
+		s.hold(token)
+	}
+	return func() { s.release(tokens) }, nil
+}
+
+// Create creates a lock with the given depth, duration, owner and root (name). The depth of zero means that the lock
+// applies only to the root, otherwise the lock also applies to all of its descendants.
+//
+// The error webdav.ErrLocked is returned if the root is already covered by another lock.
+func (s *WebDAVLockSystem) Create(now time.Time, details webdav.LockDetails) (string, error) {
+	log.Debug("[lettuce:webdav] createLock",
+		log.String("root", details.Root),
+		log.Bool("zero_depth", details.ZeroDepth),
+		log.String("duration", details.Duration.String()))
+
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	lk := webDAVLock{
+		Duration:  details.Duration,
+		OwnerXML:  details.OwnerXML,
+		Root:      webDAVLockPath(details.Root),
+		Token:     "opaquelocktoken:" + hex.EncodeToString(b),
+		ZeroDepth: details.ZeroDepth,
+	}
+	lk.expire(now)
+
+	err := s.serialize(func(ctx context.Context) error {
+		locks, err := s.locks(ctx, now)
+		if err != nil {
+			return err
+		}
+
+		for _, l := range locks {
+			if l.Root == lk.Root || (!l.ZeroDepth && descends(lk.Root, l.Root)) ||
+				(!lk.ZeroDepth && descends(l.Root, lk.Root)) {
+				return webdav.ErrLocked
+			}
+		}
+		return s.put(ctx, lk, true)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if lk.Duration < 0 {
+		s.lease(lk.Token)
+	}
+	return lk.Token, nil
+}
+
+// Refresh refreshes the lock with the given token using the provided duration.
+//
+// The error webdav.ErrNoSuchLock is returned if the lock does not exist or has expired.
+func (s *WebDAVLockSystem) Refresh(now time.Time, token string, duration time.Duration) (webdav.LockDetails, error) {
+	log.Debug("[lettuce:webdav] refreshLock", log.String("duration", duration.String()))
+
+	var details webdav.LockDetails
+	err := s.serialize(func(ctx context.Context) error {
+		lk, err := s.lock(ctx, now, token)
+		if err != nil {
+			return err
+		}
+
+		if lk.held(now) {
+			return webdav.ErrLocked
+		}
+
+		lk.Duration = duration
+		lk.expire(now)
+		if err := s.put(ctx, lk, false); err != nil {
+			return err
+		}
+
+		details = webdav.LockDetails{
+			Duration:  lk.Duration,
+			OwnerXML:  lk.OwnerXML,
+			Root:      lk.Root,
+			ZeroDepth: lk.ZeroDepth,
+		}
+		return nil
+	})
+
+	if err == nil && duration < 0 {
+		s.lease(token)
+	}
+	return details, err
+}
+
+// Unlock unlocks the lock with the given token.
+//
+// The error webdav.ErrNoSuchLock is returned if the lock does not exist or has expired.
+func (s *WebDAVLockSystem) Unlock(now time.Time, token string) error {
+	log.Debug("[lettuce:webdav] unlock")
+
+	err := s.serialize(func(ctx context.Context) error {
+		lk, err := s.lock(ctx, now, token)
+		if err != nil {
+			return err
+		}
+
+		if lk.held(now) {
+			return webdav.ErrLocked
+		}
+		return s.remove(ctx, token)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.leases, token)
+	return nil
+}
+
+// hold adds the lock with the given token to the locks held by the WebDAVLockSystem, which are renewed along with their
+// lease until they are released.
+func (s *WebDAVLockSystem) hold(token string) {
+	s.mutex.Lock()
+	s.holds[token] = true
+	s.mutex.Unlock()
+
+	s.lease(token)
+}
+
+// lease adds the lock with the given token to the locks with a lease renewed by the WebDAVLockSystem, starting the
+// renewal if it is not running.
+func (s *WebDAVLockSystem) lease(token string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.leases[token] = true
+	if !s.renewing {
+		s.renewing = true
+		go s.renew()
+	}
+}
+
+// lock returns the unexpired lock with the given token.
+func (s *WebDAVLockSystem) lock(ctx context.Context, now time.Time, token string) (webDAVLock, error) {
+	if !validToken(token) {
+		return webDAVLock{}, webdav.ErrNoSuchLock
+	}
+
+	e, err := s.let.cluster.Filer().Stat(ctx, path.Join(s.dir, token))
+	if err != nil {
+		if errors.Is(err, gofs.ErrNotExist) {
+			return webDAVLock{}, webdav.ErrNoSuchLock
+		}
+		return webDAVLock{}, err
+	}
+
+	lk, err := decodeWebDAVLock(e)
+	if err != nil {
+		return lk, err
+	}
+
+	if lk.expired(now) {
+		return lk, webdav.ErrNoSuchLock
+	}
+	return lk, nil
+}
+
+// locks returns the unexpired locks, removing any locks that have expired.
+func (s *WebDAVLockSystem) locks(ctx context.Context, now time.Time) ([]webDAVLock, error) {
+	var entries []*filer.Entry
+	opts := filer.ListOptions{Limit: dirBatchSize}
+	for {
+		batch, err := s.let.cluster.Filer().List(ctx, s.dir, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, batch...)
+		if len(batch) < dirBatchSize {
+			break
+		}
+		opts.StartFrom = batch[len(batch)-1].Name()
+	}
+
+	var locks []webDAVLock
+	for _, e := range entries {
+		lk, err := decodeWebDAVLock(e)
+		if err != nil {
+			log.Warn("[lettuce:webdav] skipping invalid lock", log.String("name", e.Name()), log.Err(err))
+			continue
+		}
+
+		if lk.expired(now) {
+			if err := s.remove(ctx, lk.Token); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		locks = append(locks, lk)
+	}
+	return locks, nil
+}
+
+// put stores the provided lock, creating the entry for the lock if create is true.
+func (s *WebDAVLockSystem) put(ctx context.Context, lk webDAVLock, create bool) error {
+	b, err := json.Marshal(lk)
+	if err != nil {
+		return err
+	}
+
+	name := path.Join(s.dir, lk.Token)
+	var e *filer.Entry
+	if create {
+		e, err = s.let.cluster.Filer().Create(ctx, name, 0o600)
+	} else {
+		e, err = s.let.cluster.Filer().Stat(ctx, name)
+	}
+	if err != nil {
+		return err
+	}
+
+	e.SetExtended(webDAVLockKey, b)
+	return s.let.cluster.Filer().Update(ctx, e)
+}
+
+// remove removes the lock with the given token.
+func (s *WebDAVLockSystem) remove(ctx context.Context, token string) error {
+	if _, err := s.let.cluster.Filer().Remove(ctx, path.Join(s.dir, token)); err != nil &&
+		!errors.Is(err, gofs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// release releases the holds on the locks with the given tokens that were confirmed by Confirm.
+//
+// The holds are no longer renewed once they are released, so if they cannot be removed from the locks (e.g. because
+// the filer is unavailable), they end with their lease instead.
+func (s *WebDAVLockSystem) release(tokens []string) {
+	s.mutex.Lock()
+	for _, token := range tokens {
+		delete(s.holds, token)
+	}
+	s.mutex.Unlock()
+
+	err := s.serialize(func(ctx context.Context) error {
+		for _, token := range tokens {
+			lk, err := s.lock(ctx, time.Now(), token)
+			if err != nil {
+				if errors.Is(err, webdav.ErrNoSuchLock) {
+					continue
+				}
+				return err
+			}
+
+			if lk.HeldBy != s.owner {
+				continue
+			}
+
+			lk.HeldBy, lk.HeldNs = "", 0
+			if err := s.put(ctx, lk, false); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Error("[lettuce:webdav] could not release locks, which remain held until their lease ends",
+			log.String("lease", webDAVLeaseTTL.String()),
+			log.Err(err))
+	}
+}
+
+// renew renews the leases for locks with an infinite timeout and for held locks every third of webDAVLeaseTTL, and
+// stops once there are no leases left to renew.
+func (s *WebDAVLockSystem) renew() {
+	ticker := time.NewTicker(webDAVLeaseTTL / 3)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		s.mutex.Lock()
+		if len(s.leases) == 0 {
+			s.renewing = false
+			s.mutex.Unlock()
+			return
+		}
+		tokens := slices.Collect(maps.Keys(s.leases))
+		s.mutex.Unlock()
+
+		for _, token := range tokens {
+			if !s.renewLease(token) {
+				s.mutex.Lock()
+				delete(s.leases, token)
+				s.mutex.Unlock()
+			}
+		}
+	}
+}
+
+// renewLease renews the lease for the lock with the given token, along with the hold on the lock if it is held by the
+// WebDAVLockSystem and has not been released, and returns whether the lease should continue to be renewed. Leases that could not be renewed due
+// to an error are retried, since the lock remains valid until its lease ends.
+func (s *WebDAVLockSystem) renewLease(token string) bool {
+	s.mutex.Lock()
+	holding := s.holds[token]
+	s.mutex.Unlock()
+
+	renew := true
+	err := s.serialize(func(ctx context.Context) error {
+		now := time.Now()
+		lk, err := s.lock(ctx, now, token)
+		if err != nil {
+			if errors.Is(err, webdav.ErrNoSuchLock) {
+				renew = false
+				return nil
+			}
+			return err
+		}
+
+		held := holding && lk.HeldBy == s.owner && lk.HeldNs != 0
+		if lk.Duration >= 0 && !held {
+			renew = false
+			return nil
+		}
+
+		if lk.Duration < 0 {
+			lk.expire(now)
+		}
+
+		if held {
+			lk.HeldNs = now.Add(webDAVLeaseTTL).UnixNano()
+		}
+		return s.put(ctx, lk, false)
+	})
+	if err != nil {
+		log.Warn("[lettuce:webdav] could not renew lock lease", log.Err(err))
+	}
+	return renew
+}
+
+// serialize calls fn while holding the distributed lock for the lock directory, so that changes to locks made by
+// different WebDAV servers do not interleave. The distributed lock is shared by every WebDAV server using the lock
+// directory, so fn should make as few filer requests as possible.
+func (s *WebDAVLockSystem) serialize(fn func(context.Context) error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), webDAVMutexTTL)
+	defer cancel()
+
+	f := s.let.cluster.Filer()
+	for {
+		token, err := f.TryLock(ctx, s.dir, webDAVMutexTTL, s.owner)
+		if err == nil {
+			defer func() {
+				if err := f.Unlock(context.Background(), s.dir, token); err != nil {
+					log.Error("[lettuce:webdav] could not release lock directory", log.Err(err))
+				}
+			}()
+			return fn(ctx)
+		}
+
+		if !errors.Is(err, filer.ErrLocked) {
+			return err
+		}
+
+		select {
+		case <-time.After(webDAVMutexRetryInterval):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// expire sets the expiration time for the lock relative to now. Locks with a negative duration (infinite timeout)
+// expire at the end of their lease, which is renewed by the WebDAV server that created them (see webDAVLeaseTTL).
+func (l *webDAVLock) expire(now time.Time) {
+	d := l.Duration
+	if d < 0 {
+		d = webDAVLeaseTTL
+	}
+	l.ExpiresNs = now.Add(d).UnixNano()
+}
+
+// expired returns whether the lock has expired at now. A held lock does not expire until it is released, and a lock
+// without an expiration time is expired, since there is no WebDAV server renewing its lease.
+func (l webDAVLock) expired(now time.Time) bool {
+	return !l.held(now) && now.UnixNano() >= l.ExpiresNs
+}
+
+// held returns whether the lock is held at now by a request confirmed using WebDAVLockSystem.Confirm.
+func (l webDAVLock) held(now time.Time) bool {
+	return l.HeldBy != "" && now.UnixNano() < l.HeldNs
+}
+
+// confirm returns the first lock with a token in the given conditions that covers the named resource and is not held,
+// and whether such a lock exists.
+func confirm(locks []webDAVLock, now time.Time, name string, conditions ...webdav.Condition) (webDAVLock, bool) {
+	for _, c := range conditions {
+		for _, l := range locks {
+			if l.Token != c.Token || l.held(now) {
+				continue
+			}
+
+			if l.Root == name || (!l.ZeroDepth && descends(name, l.Root)) {
+				return l, true
+			}
+		}
+	}
+	return webDAVLock{}, false
+}
+
+// descends returns whether name is a descendant of dir.
+func descends(name string, dir string) bool {
+	return name != dir && (dir == "/" || strings.HasPrefix(name, dir+"/"))
+}
+
+func decodeWebDAVLock(e *filer.Entry) (webDAVLock, error) {
+	var lk webDAVLock
+	b, ok := e.Extended(webDAVLockKey)
+	if !ok {
+		return lk, errors.New("lock details are missing")
+	}
+	return lk, json.Unmarshal(b, &lk)
+}
+
+// validToken returns whether the token has the format of tokens created by WebDAVLockSystem, which prevents tokens
+// supplied by clients from naming entries outside the lock directory.
+func validToken(token string) bool {
+	t, ok := strings.CutPrefix(token, "opaquelocktoken:")
+	if !ok {
+		return false
+	}
+	_, err := hex.DecodeString(t)
+	return err == nil && len(t) == 32
+}
+
+// webDAVLockPath returns the cleaned, slash-prefixed form of a WebDAV resource name used for comparing lock roots.
+func webDAVLockPath(name string) string {
+	return path.Clean("/" + name)
+}
@@ -0,0 +1,175 @@
+package lettuce
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"golang.org/x/net/webdav"
+)
+
+const (
+	testWebDAVTokenA = "opaquelocktoken:00112233445566778899aabbccddeeff"
+	testWebDAVTokenB = "opaquelocktoken:ffeeddccbbaa99887766554433221100"
+)
+
+func TestWebDAVLockConfirm(t *testing.T) {
+	now := time.Unix(1000, 0)
+	locks := []webDAVLock{
+		{Root: "/a", Token: testWebDAVTokenA},
+		{Root: "/b", Token: testWebDAVTokenB, ZeroDepth: true},
+		{Root: "/c", Token: "held", HeldBy: "other", HeldNs: now.Add(time.Second).UnixNano()},
+		{Root: "/d", Token: "released", HeldBy: "other", HeldNs: now.UnixNano()},
+	}
+
+	tests := []struct {
+		name       string
+		conditions []webdav.Condition
+		want       string
+	}{
+		{name: "/a", conditions: []webdav.Condition{{Token: testWebDAVTokenA}}, want: testWebDAVTokenA},
+		{name: "/a/x/y", conditions: []webdav.Condition{{Token: testWebDAVTokenA}}, want: testWebDAVTokenA},
+		{name: "/ab", conditions: []webdav.Condition{{Token: testWebDAVTokenA}}},
+		{name: "/b", conditions: []webdav.Condition{{Token: testWebDAVTokenB}}, want: testWebDAVTokenB},
+		{name: "/b/x", conditions: []webdav.Condition{{Token: testWebDAVTokenB}}},
+		{name: "/a", conditions: []webdav.Condition{{Token: testWebDAVTokenB}}},
+		{
+			name:       "/b",
+			conditions: []webdav.Condition{{Token: testWebDAVTokenA}, {Token: testWebDAVTokenB}},
+			want:       testWebDAVTokenB,
+		},
+		{name: "/c", conditions: []webdav.Condition{{Token: "held"}}},
+		{name: "/d", conditions: []webdav.Condition{{Token: "released"}}, want: "released"},
+		{name: "/a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lk, ok := confirm(locks, now, tt.name, tt.conditions...)
+			assert.Equal(t, tt.want != "", ok)
+			assert.Equal(t, tt.want, lk.Token)
+		})
+	}
+}
+
+func TestWebDAVLockDescends(t *testing.T) {
+	tests := []struct {
+		name string
+		dir  string
+		want bool
+	}{
+		{name: "/a/b", dir: "/a", want: true},
+		{name: "/a/b/c", dir: "/a", want: true},
+		{name: "/a", dir: "/", want: true},
+		{name: "/a", dir: "/a"},
+		{name: "/", dir: "/"},
+		{name: "/ab", dir: "/a"},
+		{name: "/a", dir: "/a/b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+" "+tt.dir, func(t *testing.T) {
+			assert.Equal(t, tt.want, descends(tt.name, tt.dir))
+		})
+	}
+}
+
+func TestWebDAVLockExpired(t *testing.T) {
+	now := time.Unix(1000, 0)
+
+	tests := []struct {
+		name        string
+		lk          webDAVLock
+		wantExpired bool
+		wantHeld    bool
+	}{
+		{name: "unexpired", lk: webDAVLock{ExpiresNs: now.Add(time.Second).UnixNano()}},
+		{name: "expired", lk: webDAVLock{ExpiresNs: now.UnixNano()}, wantExpired: true},
+		{name: "no expiration", lk: webDAVLock{}, wantExpired: true},
+		{
+			name:     "expired while held",
+			lk:       webDAVLock{ExpiresNs: now.UnixNano(), HeldBy: "owner", HeldNs: now.Add(time.Second).UnixNano()},
+			wantHeld: true,
+		},
+		{
+			name:        "expired after hold",
+			lk:          webDAVLock{ExpiresNs: now.UnixNano(), HeldBy: "owner", HeldNs: now.UnixNano()},
+			wantExpired: true,
+		},
+		{
+			name: "hold without owner",
+			lk:   webDAVLock{ExpiresNs: now.Add(time.Second).UnixNano(), HeldNs: now.Add(time.Second).UnixNano()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantExpired, tt.lk.expired(now))
+			assert.Equal(t, tt.wantHeld, tt.lk.held(now))
+		})
+	}
+}
+
+func TestWebDAVLockExpire(t *testing.T) {
+	now := time.Unix(1000, 0)
+
+	tests := []struct {
+		name     string
+		duration time.Duration
+		want     time.Time
+	}{
+		{name: "finite", duration: time.Hour, want: now.Add(time.Hour)},
+		{name: "infinite", duration: -1, want: now.Add(webDAVLeaseTTL)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lk := webDAVLock{Duration: tt.duration}
+			lk.expire(now)
+			assert.Equal(t, tt.want.UnixNano(), lk.ExpiresNs)
+		})
+	}
+}
+
+func TestWebDAVLockValidToken(t *testing.T) {
+	tests := []struct {
+		token string
+		want  bool
+	}{
+		{token: testWebDAVTokenA, want: true},
+		{token: "00112233445566778899aabbccddeeff"},
+		{token: "opaquelocktoken:"},
+		{token: "opaquelocktoken:00112233445566778899aabbccddee"},
+		{token: "opaquelocktoken:00112233445566778899aabbccddeeff00"},
+		{token: "opaquelocktoken:0011223344556677889/aabbccddeeff"},
+		{token: "opaquelocktoken:../../../../../../../../etc/passwd"},
+		{token: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.token, func(t *testing.T) {
+			assert.Equal(t, tt.want, validToken(tt.token))
+		})
+	}
+}
+
+func TestWebDAVLockPath(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "", want: "/"},
+		{name: "/", want: "/"},
+		{name: "a", want: "/a"},
+		{name: "/a/", want: "/a"},
+		{name: "/a//b/./c", want: "/a/b/c"},
+		{name: "/a/../../b", want: "/b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, webDAVLockPath(tt.name))
+		})
+	}
+}
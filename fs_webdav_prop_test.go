@@ -0,0 +1,50 @@
+package lettuce
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebDAVPropName(t *testing.T) {
+	tests := []struct {
+		key  string
+		want xml.Name
+		ok   bool
+	}{
+		{key: "webdav-prop-{DAV:}displayname", want: xml.Name{Space: "DAV:", Local: "displayname"}, ok: true},
+		{key: "webdav-prop-{http://example.com/ns}color", want: xml.Name{Space: "http://example.com/ns", Local: "color"}, ok: true},
+		{key: "webdav-prop-{}color", want: xml.Name{Local: "color"}, ok: true},
+		{key: "webdav-prop-{urn:a}b}c", want: xml.Name{Space: "urn:a", Local: "b}c"}, ok: true},
+		{key: "webdav-prop-{DAV:}"},
+		{key: "webdav-prop-{DAV:displayname"},
+		{key: "webdav-prop-displayname"},
+		{key: "user.comment"},
+		{key: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			n, ok := webDAVPropName(tt.key)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.want, n)
+		})
+	}
+}
+
+func TestWebDAVPropKey(t *testing.T) {
+	names := []xml.Name{
+		{Space: "DAV:", Local: "displayname"},
+		{Space: "http://example.com/ns", Local: "color"},
+		{Local: "color"},
+	}
+
+	for _, n := range names {
+		t.Run(n.Space+" "+n.Local, func(t *testing.T) {
+			got, ok := webDAVPropName(webDAVPropKey(n))
+			assert.True(t, ok)
+			assert.Equal(t, n, got)
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package lettuce
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/transientvariable/lettuce/cluster/filer"
+	"github.com/transientvariable/log-go"
+
+	gofs "io/fs"
+)
+
+// Flags that control the behavior of Setxattr, which have the same semantics as XATTR_CREATE and XATTR_REPLACE for
+// setxattr(2).
+const (
+	XattrCreate  = 0x1
+	XattrReplace = 0x2
+)
+
+// Conventions used by the SeaweedFS FUSE mount for storing extended attributes in Entry.extended.
+const (
+	xattrKeyPrefix    = "xattr-"
+	xattrNameSizeMax  = 255
+	xattrValueSizeMax = 65536
+)
+
+// xattrNamespaces are the namespaces permitted for extended attribute names (see xattr(7)).
+var xattrNamespaces = []string{"security.", "system.", "trusted.", "user."}
+
+// Getxattr returns the value of the extended attribute attr for the named file. If the file is a symbolic link, the
+// attribute is retrieved from the link's target.
+//
+// Names must be qualified by a namespace (e.g. "user.checksum"). If the attribute does not exist, the returned error
+// wraps ErrNoXattr.
+func (l *Lettuce) Getxattr(name string, attr string) ([]byte, error) {
+	return l.GetxattrContext(context.Background(), name, attr)
+}
+
+// GetxattrContext is like Getxattr, but uses the provided context.Context for the operation.
+func (l *Lettuce) GetxattrContext(ctx context.Context, name string, attr string) ([]byte, error) {
+	log.Debug("[lettuce] getxattr", log.String("name", name), log.String("attr", attr))
+
+	e, err := stat(ctx, l, name)
+	if err == nil {
+		var v []byte
+		if v, err = getxattr(e, attr); err == nil {
+			return v, nil
+		}
+	}
+	return nil, fmt.Errorf("lettuce: %w", &gofs.PathError{Op: "getxattr", Path: name, Err: err})
+}
+
+// Listxattr returns the sorted names of the extended attributes for the named file. If the file is a symbolic link,
+// the attributes of the link's target are listed.
+func (l *Lettuce) Listxattr(name string) ([]string, error) {
+	return l.ListxattrContext(context.Background(), name)
+}
+
+// ListxattrContext is like Listxattr, but uses the provided context.Context for the operation.
+func (l *Lettuce) ListxattrContext(ctx context.Context, name string) ([]string, error) {
+	log.Debug("[lettuce] listxattr", log.String("name", name))
+
+	e, err := stat(ctx, l, name)
+	if err != nil {
+		return nil, fmt.Errorf("lettuce: %w", &gofs.PathError{Op: "listxattr", Path: name, Err: err})
+	}
+	return listxattr(e), nil
+}
+
+// Removexattr removes the extended attribute attr from the named file. If the file is a symbolic link, the attribute
+// is removed from the link's target.
+func (l *Lettuce) Removexattr(name string, attr string) error {
+	return l.RemovexattrContext(context.Background(), name, attr)
+}
+
+// RemovexattrContext is like Removexattr, but uses the provided context.Context for the operation.
+func (l *Lettuce) RemovexattrContext(ctx context.Context, name string, attr string) error {
+	log.Debug("[lettuce] removexattr", log.String("name", name), log.String("attr", attr))
+
+	if err := chattr(ctx, l, name, true, func(e *filer.Entry) error { return removexattr(e, attr) }); err != nil {
+		return fmt.Errorf("lettuce: %w", &gofs.PathError{Op: "removexattr", Path: name, Err: err})
+	}
+	return nil
+}
+
+// Setxattr sets the value of the extended attribute attr for the named file. If the file is a symbolic link, the
+// attribute is set on the link's target.
+//
+// The flags XattrCreate and XattrReplace can be used to require that the attribute does not, or does already exist,
+// respectively. Names are limited to 255 bytes and values to 64 KiB.
+func (l *Lettuce) Setxattr(name string, attr string, data []byte, flags int) error {
+	return l.SetxattrContext(context.Background(), name, attr, data, flags)
+}
+
+// SetxattrContext is like Setxattr, but uses the provided context.Context for the operation.
+func (l *Lettuce) SetxattrContext(ctx context.Context, name string, attr string, data []byte, flags int) error {
+	log.Debug("[lettuce] setxattr",
+		log.String("name", name),
+		log.String("attr", attr),
+		log.Int("size", len(data)),
+		log.Int("flags", flags))
+
+	if err := chattr(ctx, l, name, true, func(e *filer.Entry) error { return setxattr(e, attr, data, flags) }); err != nil {
+		return fmt.Errorf("lettuce: %w", &gofs.PathError{Op: "setxattr", Path: name, Err: err})
+	}
+	return nil
+}
+
+func getxattr(e *filer.Entry, attr string) ([]byte, error) {
+	if err := checkXattrName(attr); err != nil {
+		return nil, err
+	}
+
+	v, ok := e.Extended(xattrKeyPrefix + attr)
+	if !ok {
+		return nil, ErrNoXattr
+	}
+	return v, nil
+}
+
+func listxattr(e *filer.Entry) []string {
+	var attrs []string
+	for _, k := range e.ExtendedKeys() {
+		if a, ok := strings.CutPrefix(k, xattrKeyPrefix); ok {
+			attrs = append(attrs, a)
+		}
+	}
+	return attrs
+}
+
+func removexattr(e *filer.Entry, attr string) error {
+	if err := checkXattrName(attr); err != nil {
+		return err
+	}
+
+	if _, ok := e.Extended(xattrKeyPrefix + attr); !ok {
+		return ErrNoXattr
+	}
+	e.DeleteExtended(xattrKeyPrefix + attr)
+	return nil
+}
+
+func setxattr(e *filer.Entry, attr string, data []byte, flags int) error {
+	_, ok := e.Extended(xattrKeyPrefix + attr)
+	if err := checkSetxattr(attr, len(data), flags, ok); err != nil {
+		return err
+	}
+	e.SetExtended(xattrKeyPrefix+attr, data)
+	return nil
+}
+
+// checkSetxattr returns an error if the extended attribute attr with a value of the provided size cannot be set using
+// flags, where exists is whether the attribute already exists.
+func checkSetxattr(attr string, size int, flags int, exists bool) error {
+	if err := checkXattrName(attr); err != nil {
+		return err
+	}
+
+	if flags&^(XattrCreate|XattrReplace) != 0 || flags == XattrCreate|XattrReplace {
+		return gofs.ErrInvalid
+	}
+
+	if size > xattrValueSizeMax {
+		return ErrXattrTooLarge
+	}
+
+	if exists && flags&XattrCreate != 0 {
+		return gofs.ErrExist
+	}
+
+	if !exists && flags&XattrReplace != 0 {
+		return ErrNoXattr
+	}
+	return nil
+}
+
+func checkXattrName(attr string) error {
+	if len(attr) > xattrNameSizeMax {
+		return ErrXattrTooLarge
+	}
+
+	for _, ns := range xattrNamespaces {
+		if len(attr) > len(ns) && strings.HasPrefix(attr, ns) {
+			return nil
+		}
+	}
+	return gofs.ErrInvalid
+}
@@ -0,0 +1,75 @@
+package lettuce
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	gofs "io/fs"
+)
+
+func TestCheckXattrName(t *testing.T) {
+	tests := []struct {
+		attr string
+		err  error
+	}{
+		{attr: "user.checksum"},
+		{attr: "security.selinux"},
+		{attr: "system.posix_acl_access"},
+		{attr: "trusted.overlay.opaque"},
+		{attr: "user." + strings.Repeat("a", xattrNameSizeMax-len("user."))},
+		{attr: "user." + strings.Repeat("a", xattrNameSizeMax), err: ErrXattrTooLarge},
+		{attr: "user.", err: gofs.ErrInvalid},
+		{attr: "checksum", err: gofs.ErrInvalid},
+		{attr: "other.checksum", err: gofs.ErrInvalid},
+		{attr: "User.checksum", err: gofs.ErrInvalid},
+		{attr: "", err: gofs.ErrInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.attr, func(t *testing.T) {
+			err := checkXattrName(tt.attr)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestCheckSetxattr(t *testing.T) {
+	tests := []struct {
+		name   string
+		attr   string
+		size   int
+		flags  int
+		exists bool
+		err    error
+	}{
+		{name: "create or replace new", attr: "user.a"},
+		{name: "create or replace existing", attr: "user.a", exists: true},
+		{name: "create new", attr: "user.a", flags: XattrCreate},
+		{name: "create existing", attr: "user.a", flags: XattrCreate, exists: true, err: gofs.ErrExist},
+		{name: "replace existing", attr: "user.a", flags: XattrReplace, exists: true},
+		{name: "replace new", attr: "user.a", flags: XattrReplace, err: ErrNoXattr},
+		{name: "create and replace", attr: "user.a", flags: XattrCreate | XattrReplace, err: gofs.ErrInvalid},
+		{name: "unknown flag", attr: "user.a", flags: 0x4, err: gofs.ErrInvalid},
+		{name: "maximum value size", attr: "user.a", size: xattrValueSizeMax},
+		{name: "value too large", attr: "user.a", size: xattrValueSizeMax + 1, err: ErrXattrTooLarge},
+		{name: "invalid name", attr: "a", err: gofs.ErrInvalid},
+		{name: "invalid name before flags", attr: "a", flags: XattrCreate, exists: true, err: gofs.ErrInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkSetxattr(tt.attr, tt.size, tt.flags, tt.exists)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,206 @@
+package iam
+
+import (
+	"crypto/rand"
+	"fmt"
+	"slices"
+
+	"github.com/transientvariable/lettuce/pb/iam_pb"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// Enumeration of actions that may be granted to an identity. An action may be restricted to a bucket using Action.
+const (
+	ActionAdmin   = "Admin"
+	ActionList    = "List"
+	ActionRead    = "Read"
+	ActionTagging = "Tagging"
+	ActionWrite   = "Write"
+)
+
+const (
+	accessKeyChars = "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	accessKeyLen   = 20
+	secretKeyChars = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+	secretKeyLen   = 40
+)
+
+// Action returns the action restricted to the provided bucket (e.g. Read:reports).
+func Action(action string, bucket string) string {
+	return action + ":" + bucket
+}
+
+// Config is the S3 identity configuration loaded by a Store.
+//
+// Changes made to a Config are not persisted until it is saved using Store.Save.
+type Config struct {
+	pb      *iam_pb.S3ApiConfiguration
+	version string
+}
+
+// AccessKey returns the identity with the provided access key, and whether the access key exists.
+func (c *Config) AccessKey(accessKey string) (*iam_pb.Identity, bool) {
+	for _, id := range c.pb.GetIdentities() {
+		for _, cred := range id.GetCredentials() {
+			if cred.GetAccessKey() == accessKey {
+				return id, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// AddAccessKey adds the provided access key and secret key to the credentials for the named identity.
+func (c *Config) AddAccessKey(name string, accessKey string, secretKey string) error {
+	id, ok := c.Identity(name)
+	if !ok {
+		return fmt.Errorf("iam: %s: %w", name, ErrIdentityNotFound)
+	}
+
+	if accessKey == "" || secretKey == "" {
+		return fmt.Errorf("iam: access key and secret key are required")
+	}
+
+	if _, ok := c.AccessKey(accessKey); ok {
+		return fmt.Errorf("iam: %s: %w", accessKey, ErrAccessKeyExists)
+	}
+
+	id.Credentials = append(id.Credentials, &iam_pb.Credential{AccessKey: accessKey, SecretKey: secretKey})
+	return nil
+}
+
+// CreateAccessKey creates a random access key and secret key for the named identity and returns the new credential.
+func (c *Config) CreateAccessKey(name string) (*iam_pb.Credential, error) {
+	accessKey := randomKey(accessKeyChars, accessKeyLen)
+	for _, ok := c.AccessKey(accessKey); ok; _, ok = c.AccessKey(accessKey) {
+		accessKey = randomKey(accessKeyChars, accessKeyLen)
+	}
+
+	secretKey := randomKey(secretKeyChars, secretKeyLen)
+	if err := c.AddAccessKey(name, accessKey, secretKey); err != nil {
+		return nil, err
+	}
+	return &iam_pb.Credential{AccessKey: accessKey, SecretKey: secretKey}, nil
+}
+
+// CreateIdentity creates an identity with the provided name, account, and granted actions. The account may be nil.
+func (c *Config) CreateIdentity(name string, account *iam_pb.Account, actions ...string) (*iam_pb.Identity, error) {
+	if name == "" {
+		return nil, fmt.Errorf("iam: identity name is required")
+	}
+
+	if _, ok := c.Identity(name); ok {
+		return nil, fmt.Errorf("iam: %s: %w", name, ErrIdentityExists)
+	}
+
+	id := &iam_pb.Identity{Name: name, Account: account}
+	for _, a := range actions {
+		if !slices.Contains(id.Actions, a) {
+			id.Actions = append(id.Actions, a)
+		}
+	}
+	c.pb.Identities = append(c.pb.Identities, id)
+	return id, nil
+}
+
+// DeleteAccessKey removes the provided access key from the credentials for the named identity.
+func (c *Config) DeleteAccessKey(name string, accessKey string) error {
+	id, ok := c.Identity(name)
+	if !ok {
+		return fmt.Errorf("iam: %s: %w", name, ErrIdentityNotFound)
+	}
+
+	i := slices.IndexFunc(id.Credentials, func(cred *iam_pb.Credential) bool {
+		return cred.GetAccessKey() == accessKey
+	})
+	if i < 0 {
+		return fmt.Errorf("iam: %s: %w", accessKey, ErrAccessKeyNotFound)
+	}
+	id.Credentials = slices.Delete(id.Credentials, i, i+1)
+	return nil
+}
+
+// DeleteIdentity removes the named identity along with its credentials.
+func (c *Config) DeleteIdentity(name string) error {
+	i := slices.IndexFunc(c.pb.Identities, func(id *iam_pb.Identity) bool { return id.GetName() == name })
+	if i < 0 {
+		return fmt.Errorf("iam: %s: %w", name, ErrIdentityNotFound)
+	}
+	c.pb.Identities = slices.Delete(c.pb.Identities, i, i+1)
+	return nil
+}
+
+// Grant grants the provided actions to the named identity. Actions that are already granted are ignored.
+func (c *Config) Grant(name string, actions ...string) error {
+	id, ok := c.Identity(name)
+	if !ok {
+		return fmt.Errorf("iam: %s: %w", name, ErrIdentityNotFound)
+	}
+
+	for _, a := range actions {
+		if !slices.Contains(id.Actions, a) {
+			id.Actions = append(id.Actions, a)
+		}
+	}
+	return nil
+}
+
+// Identities returns the identities in the Config.
+func (c *Config) Identities() []*iam_pb.Identity {
+	return c.pb.GetIdentities()
+}
+
+// Identity returns the named identity, and whether the identity exists.
+func (c *Config) Identity(name string) (*iam_pb.Identity, bool) {
+	for _, id := range c.pb.GetIdentities() {
+		if id.GetName() == name {
+			return id, true
+		}
+	}
+	return nil, false
+}
+
+// PB returns the iam_pb.S3ApiConfiguration for the Config, which may be modified directly for changes not covered by
+// the methods of Config (e.g. accounts).
+func (c *Config) PB() *iam_pb.S3ApiConfiguration {
+	return c.pb
+}
+
+// Revoke revokes the provided actions from the named identity. Actions that are not granted are ignored.
+func (c *Config) Revoke(name string, actions ...string) error {
+	id, ok := c.Identity(name)
+	if !ok {
+		return fmt.Errorf("iam: %s: %w", name, ErrIdentityNotFound)
+	}
+
+	id.Actions = slices.DeleteFunc(id.Actions, func(a string) bool { return slices.Contains(actions, a) })
+	return nil
+}
+
+// String returns a string representation of the Config. Secret keys are omitted.
+func (c *Config) String() string {
+	pb := proto.Clone(c.pb).(*iam_pb.S3ApiConfiguration)
+	for _, id := range pb.GetIdentities() {
+		for _, cred := range id.GetCredentials() {
+			cred.SecretKey = ""
+		}
+	}
+	return pb.String()
+}
+
+// randomKey returns a random key of length n using the provided characters. Random bytes that would bias the choice
+// of characters are discarded.
+func randomKey(chars string, n int) string {
+	limit := 256 - 256%len(chars)
+	b := make([]byte, 0, n)
+	r := make([]byte, 1)
+	for len(b) < n {
+		// Read never returns an error and always fills the provided slice.
+		_, _ = rand.Read(r)
+		if int(r[0]) < limit {
+			b = append(b, chars[int(r[0])%len(chars)])
+		}
+	}
+	return string(b)
+}
@@ -0,0 +1,210 @@
+package iam
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/transientvariable/lettuce/pb/iam_pb"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigIdentity(t *testing.T) {
+	c := testConfig(t)
+
+	tests := []struct {
+		name    string
+		id      string
+		actions []string
+		want    []string
+		err     error
+	}{
+		{name: "create", id: "bob", actions: []string{ActionRead}, want: []string{ActionRead}},
+		{
+			name:    "duplicate actions",
+			id:      "carol",
+			actions: []string{ActionRead, ActionWrite, ActionRead},
+			want:    []string{ActionRead, ActionWrite},
+		},
+		{name: "exists", id: "alice", err: ErrIdentityExists},
+		{name: "name required", id: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := c.CreateIdentity(tt.id, nil, tt.actions...)
+			if tt.want == nil {
+				require.Error(t, err)
+				if tt.err != nil {
+					assert.ErrorIs(t, err, tt.err)
+				}
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, id.GetActions())
+
+			got, ok := c.Identity(tt.id)
+			require.True(t, ok)
+			assert.Same(t, id, got)
+		})
+	}
+	assert.Len(t, c.Identities(), 3)
+
+	require.NoError(t, c.DeleteIdentity("bob"))
+	_, ok := c.Identity("bob")
+	assert.False(t, ok)
+	assert.ErrorIs(t, c.DeleteIdentity("bob"), ErrIdentityNotFound)
+	assert.Len(t, c.Identities(), 2)
+}
+
+func TestConfigAccessKey(t *testing.T) {
+	tests := []struct {
+		name      string
+		id        string
+		accessKey string
+		secretKey string
+		err       error
+	}{
+		{name: "add", id: "alice", accessKey: "AKNEW", secretKey: "secret"},
+		{name: "exists", id: "alice", accessKey: "AKALICE", secretKey: "secret", err: ErrAccessKeyExists},
+		{name: "exists for other identity", id: "bob", accessKey: "AKALICE", secretKey: "secret", err: ErrAccessKeyExists},
+		{name: "identity not found", id: "dave", accessKey: "AKNEW", secretKey: "secret", err: ErrIdentityNotFound},
+		{name: "access key required", id: "alice", secretKey: "secret"},
+		{name: "secret key required", id: "alice", accessKey: "AKNEW"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := testConfig(t)
+			_, err := c.CreateIdentity("bob", nil)
+			require.NoError(t, err)
+
+			err = c.AddAccessKey(tt.id, tt.accessKey, tt.secretKey)
+			if tt.err != nil || tt.accessKey == "" || tt.secretKey == "" {
+				require.Error(t, err)
+				if tt.err != nil {
+					assert.ErrorIs(t, err, tt.err)
+				}
+				return
+			}
+			require.NoError(t, err)
+
+			id, ok := c.AccessKey(tt.accessKey)
+			require.True(t, ok)
+			assert.Equal(t, tt.id, id.GetName())
+		})
+	}
+}
+
+func TestConfigCreateAccessKey(t *testing.T) {
+	c := testConfig(t)
+
+	cred, err := c.CreateAccessKey("alice")
+	require.NoError(t, err)
+	assert.Len(t, cred.GetAccessKey(), accessKeyLen)
+	assert.Len(t, cred.GetSecretKey(), secretKeyLen)
+	assert.Empty(t, strings.Trim(cred.GetAccessKey(), accessKeyChars))
+	assert.Empty(t, strings.Trim(cred.GetSecretKey(), secretKeyChars))
+
+	id, ok := c.AccessKey(cred.GetAccessKey())
+	require.True(t, ok)
+	assert.Equal(t, "alice", id.GetName())
+
+	_, err = c.CreateAccessKey("dave")
+	assert.ErrorIs(t, err, ErrIdentityNotFound)
+}
+
+func TestConfigDeleteAccessKey(t *testing.T) {
+	tests := []struct {
+		name      string
+		id        string
+		accessKey string
+		err       error
+	}{
+		{name: "delete", id: "alice", accessKey: "AKALICE"},
+		{name: "access key not found", id: "alice", accessKey: "AKNONE", err: ErrAccessKeyNotFound},
+		{name: "identity not found", id: "dave", accessKey: "AKALICE", err: ErrIdentityNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := testConfig(t)
+
+			err := c.DeleteAccessKey(tt.id, tt.accessKey)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+
+			_, ok := c.AccessKey(tt.accessKey)
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestConfigGrantRevoke(t *testing.T) {
+	tests := []struct {
+		name   string
+		grant  []string
+		revoke []string
+		want   []string
+	}{
+		{name: "grant", grant: []string{ActionWrite}, want: []string{ActionRead, ActionWrite}},
+		{name: "grant existing", grant: []string{ActionRead}, want: []string{ActionRead}},
+		{
+			name:  "grant bucket action",
+			grant: []string{Action(ActionWrite, "reports")},
+			want:  []string{ActionRead, "Write:reports"},
+		},
+		{name: "revoke", revoke: []string{ActionRead}, want: []string{}},
+		{name: "revoke missing", revoke: []string{ActionAdmin}, want: []string{ActionRead}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := testConfig(t)
+			require.NoError(t, c.Grant("alice", tt.grant...))
+			require.NoError(t, c.Revoke("alice", tt.revoke...))
+
+			id, ok := c.Identity("alice")
+			require.True(t, ok)
+			assert.ElementsMatch(t, tt.want, id.GetActions())
+		})
+	}
+
+	c := testConfig(t)
+	assert.ErrorIs(t, c.Grant("dave", ActionRead), ErrIdentityNotFound)
+	assert.ErrorIs(t, c.Revoke("dave", ActionRead), ErrIdentityNotFound)
+}
+
+func TestConfigString(t *testing.T) {
+	c := testConfig(t)
+
+	s := c.String()
+	assert.Contains(t, s, "AKALICE")
+	assert.NotContains(t, s, "alice-secret")
+
+	id, ok := c.AccessKey("AKALICE")
+	require.True(t, ok)
+	assert.Equal(t, "alice-secret", id.GetCredentials()[0].GetSecretKey())
+}
+
+func TestVersion(t *testing.T) {
+	assert.Equal(t, version([]byte(`{"identities":[]}`)), version([]byte(`{"identities":[]}`)))
+	assert.NotEqual(t, version([]byte(`{"identities":[]}`)), version([]byte(`{"identities": []}`)))
+	assert.Len(t, version(nil), 64)
+}
+
+// testConfig returns a Config containing the identity "alice", which is granted ActionRead and has the access key
+// AKALICE.
+func testConfig(t *testing.T) *Config {
+	t.Helper()
+
+	c := &Config{pb: &iam_pb.S3ApiConfiguration{}}
+	_, err := c.CreateIdentity("alice", nil, ActionRead)
+	require.NoError(t, err)
+	require.NoError(t, c.AddAccessKey("alice", "AKALICE", "alice-secret"))
+	return c
+}
@@ -0,0 +1,19 @@
+package iam
+
+// Enumeration of errors that may be returned by identity management operations.
+const (
+	ErrAccessKeyExists   = iamError("access key already exists")
+	ErrAccessKeyNotFound = iamError("access key not found")
+	ErrConflict          = iamError("configuration was modified concurrently")
+	ErrIdentityExists    = iamError("identity already exists")
+	ErrIdentityNotFound  = iamError("identity not found")
+	ErrUnsupported       = iamError("configuration contains unsupported fields")
+)
+
+// iamError defines the type for errors that may be returned by identity management operations.
+type iamError string
+
+// Error returns the cause of an identity management operation error.
+func (e iamError) Error() string {
+	return string(e)
+}
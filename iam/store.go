@@ -0,0 +1,232 @@
+// Package iam provides management of the identities used for authenticating requests to the SeaweedFS S3 gateway,
+// which are stored in the filer as an iam_pb.S3ApiConfiguration.
+package iam
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"time"
+
+	"github.com/transientvariable/lettuce"
+	"github.com/transientvariable/lettuce/pb/iam_pb"
+	"github.com/transientvariable/log-go"
+
+	"google.golang.org/protobuf/encoding/protojson"
+
+	gofs "io/fs"
+)
+
+const (
+	// ConfigPath defines the default path relative to the filer root of the S3 identity configuration, which is the
+	// path read by the S3 gateway.
+	ConfigPath = "etc/iam/identity.json"
+
+	// LockTTL defines the default time-to-live for the distributed lock held while saving the configuration.
+	LockTTL = 10 * time.Second
+
+	// updateAttempts defines the number of times Store.Update attempts to apply changes to the configuration before
+	// returning ErrConflict.
+	updateAttempts = 5
+)
+
+// Store loads and saves the S3 identity configuration stored in the filer.
+type Store struct {
+	let     *lettuce.Lettuce
+	lockTTL time.Duration
+	owner   string
+	path    string
+}
+
+// New creates a new Store for the S3 identity configuration using the provided Lettuce and options.
+//
+// The provided Lettuce must be rooted at the filer root, since the configuration is stored under /etc.
+func New(let *lettuce.Lettuce, options ...func(*Store)) (*Store, error) {
+	if let == nil {
+		return nil, errors.New("iam: lettuce backend is required")
+	}
+
+	s := &Store{let: let, lockTTL: LockTTL, path: ConfigPath}
+	for _, opt := range options {
+		opt(s)
+	}
+
+	if s.owner == "" {
+		host, err := os.Hostname()
+		if err != nil {
+			host = "iam"
+		}
+		s.owner = fmt.Sprintf("%s:%d", host, os.Getpid())
+	}
+	return s, nil
+}
+
+// Load returns the S3 identity configuration. An empty Config is returned if the configuration does not exist.
+//
+// The error ErrUnsupported is returned if the configuration contains fields that are not described by the
+// iam_pb.S3ApiConfiguration used by Store (e.g. service accounts or policies written by newer versions of SeaweedFS),
+// since saving the configuration would remove them.
+func (s *Store) Load(ctx context.Context) (*Config, error) {
+	log.Debug("[iam] load", log.String("path", s.path))
+
+	c, err := s.load(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("iam: %w", err)
+	}
+	return c, nil
+}
+
+// Save saves the provided Config, which must have been returned by Store.Load or Store.Update.
+//
+// The configuration is only saved if it has not been modified since the Config was loaded, otherwise the error
+// ErrConflict is returned, in which case the configuration should be loaded and the changes applied again (see
+// Store.Update).
+//
+// Concurrent saves are serialized using a distributed lock, which is only acquired by Store, so changes made by other
+// tools (e.g. the s3.configure command of weed shell) between the check for modifications and the write are lost.
+func (s *Store) Save(ctx context.Context, c *Config) error {
+	log.Debug("[iam] save", log.String("path", s.path), log.String("version", c.version))
+
+	if err := s.save(ctx, c); err != nil {
+		return fmt.Errorf("iam: %w", err)
+	}
+	return nil
+}
+
+// Update loads the S3 identity configuration, applies the changes made by fn, and saves it. If the configuration is
+// modified concurrently, the configuration is loaded again and fn is retried. The error returned by fn, if any, is
+// returned by Update without saving the configuration.
+func (s *Store) Update(ctx context.Context, fn func(*Config) error) error {
+	for range updateAttempts {
+		c, err := s.Load(ctx)
+		if err != nil {
+			return err
+		}
+
+		if err := fn(c); err != nil {
+			return err
+		}
+
+		err = s.Save(ctx, c)
+		if !errors.Is(err, ErrConflict) {
+			return err
+		}
+		log.Debug("[iam] retrying update after conflict", log.String("path", s.path))
+	}
+	return fmt.Errorf("iam: %w", &gofs.PathError{Op: "update", Path: s.path, Err: ErrConflict})
+}
+
+func (s *Store) load(ctx context.Context) (*Config, error) {
+	b, err := s.let.ReadFileContext(ctx, s.path)
+	if err != nil {
+		if errors.Is(err, gofs.ErrNotExist) {
+			return &Config{pb: &iam_pb.S3ApiConfiguration{}}, nil
+		}
+		return nil, err
+	}
+
+	pb, err := decode(b)
+	if err != nil {
+		return nil, &gofs.PathError{Op: "load", Path: s.path, Err: err}
+	}
+	return &Config{pb: pb, version: version(b)}, nil
+}
+
+func (s *Store) save(ctx context.Context, c *Config) error {
+	b, err := (protojson.MarshalOptions{Indent: "  ", Multiline: true}).Marshal(c.pb)
+	if err != nil {
+		return &gofs.PathError{Op: "save", Path: s.path, Err: err}
+	}
+
+	lock, err := s.let.Lock(ctx, s.path, s.lockTTL, s.owner)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := lock.Release(context.WithoutCancel(ctx)); err != nil {
+			log.Error("[iam] could not release lock", log.String("path", s.path), log.Err(err))
+		}
+	}()
+
+	cur, err := s.load(ctx)
+	if err != nil {
+		return err
+	}
+
+	if cur.version != c.version {
+		return &gofs.PathError{Op: "save", Path: s.path, Err: ErrConflict}
+	}
+
+	if err := s.let.MkdirAllContext(ctx, path.Dir(s.path), gofs.ModeDir|0755); err != nil {
+		return err
+	}
+
+	if err := s.write(ctx, b); err != nil {
+		return err
+	}
+	c.version = version(b)
+	return nil
+}
+
+// write stores the serialized configuration inline with the entry for the configuration using a single request, since
+// the S3 gateway reloads the configuration from the content of the entry in the metadata event for the change.
+func (s *Store) write(ctx context.Context, b []byte) error {
+	f := s.let.Cluster().Filer()
+	e, err := f.Stat(ctx, s.path)
+	if err != nil {
+		if !errors.Is(err, gofs.ErrNotExist) {
+			return err
+		}
+		_, err = f.CreateWithContent(ctx, s.path, 0600, b)
+		return err
+	}
+
+	e.SetContent(b)
+	e.SetModTime(time.Now())
+	return f.Update(ctx, e)
+}
+
+// WithLockTTL sets the time-to-live for the distributed lock held while saving the configuration.
+func WithLockTTL(ttl time.Duration) func(*Store) {
+	return func(s *Store) {
+		s.lockTTL = ttl
+	}
+}
+
+// WithOwner sets the owner reported for the distributed lock held while saving the configuration. The default owner is
+// derived from the host name and process ID.
+func WithOwner(owner string) func(*Store) {
+	return func(s *Store) {
+		s.owner = owner
+	}
+}
+
+// WithPath sets the path relative to the filer root of the S3 identity configuration (default: ConfigPath).
+func WithPath(path string) func(*Store) {
+	return func(s *Store) {
+		s.path = path
+	}
+}
+
+// decode decodes the serialized configuration. Unknown fields are rejected rather than discarded, so that saving a
+// configuration does not remove them.
+func decode(b []byte) (*iam_pb.S3ApiConfiguration, error) {
+	pb := &iam_pb.S3ApiConfiguration{}
+	if err := protojson.Unmarshal(b, pb); err != nil {
+		if (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(b, &iam_pb.S3ApiConfiguration{}) == nil {
+			return nil, fmt.Errorf("%w: %v", ErrUnsupported, err)
+		}
+		return nil, err
+	}
+	return pb, nil
+}
+
+// version returns the version of the serialized configuration, which is used for detecting concurrent modifications.
+func version(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,66 @@
+package iam
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name       string
+		b          string
+		identities []string
+		wantErr    bool
+		err        error
+	}{
+		{name: "empty", b: `{}`},
+		{
+			name:       "identities",
+			b:          `{"identities":[{"name":"alice","credentials":[{"accessKey":"AK","secretKey":"SK"}],"actions":["Read"]}]}`,
+			identities: []string{"alice"},
+		},
+		{
+			name:    "unknown top-level field",
+			b:       `{"identities":[],"serviceAccounts":[{"id":"sa"}]}`,
+			wantErr: true,
+			err:     ErrUnsupported,
+		},
+		{
+			name:    "unknown identity field",
+			b:       `{"identities":[{"name":"alice","disabled":true}]}`,
+			wantErr: true,
+			err:     ErrUnsupported,
+		},
+		{
+			name:    "unknown credential field",
+			b:       `{"identities":[{"name":"alice","credentials":[{"accessKey":"AK","status":"Inactive"}]}]}`,
+			wantErr: true,
+			err:     ErrUnsupported,
+		},
+		{name: "invalid", b: `{"identities":`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pb, err := decode([]byte(tt.b))
+			if tt.wantErr {
+				require.Error(t, err)
+				if tt.err != nil {
+					assert.ErrorIs(t, err, tt.err)
+				} else {
+					assert.NotErrorIs(t, err, ErrUnsupported)
+				}
+				return
+			}
+			require.NoError(t, err)
+
+			var names []string
+			for _, id := range pb.GetIdentities() {
+				names = append(names, id.GetName())
+			}
+			assert.Equal(t, tt.identities, names)
+		})
+	}
+}
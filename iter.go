@@ -9,25 +9,26 @@ import (
 
 	"github.com/transientvariable/fs-go"
 	"github.com/transientvariable/lettuce/cluster/filer"
-	"github.com/transientvariable/lettuce/pb/filer_pb"
 	"github.com/transientvariable/log-go"
 )
 
-type dirEntry struct {
-	entry *filer.Entry
-	err   error
-}
+const (
+	// dirBatchSize defines the maximum number of entries retrieved from the filer per request when iterating over the
+	// entries in a directory.
+	dirBatchSize = 1024
+)
 
 type dirIterator struct {
+	batch   []*filer.Entry
 	ctx     context.Context
 	dir     *filer.Entry
-	entries <-chan dirEntry
+	done    bool
 	filer   *filer.Filer
 	hasNext atomic.Bool
 	let     *Lettuce
 	mutex   sync.Mutex
 	name    string
-	next    dirEntry
+	opts    filer.ListOptions
 }
 
 func newDirIterator(ctx context.Context, let *Lettuce, entry *filer.Entry) (fs.DirIterator, error) {
@@ -44,21 +45,13 @@ func newDirIterator(ctx context.Context, let *Lettuce, entry *filer.Entry) (fs.D
 		log.String("name", entry.Name()),
 		log.String("path", entry.Path().String()))
 
-	f := let.cluster.Filer()
-	c, err := f.PB().ListEntries(ctx, &filer_pb.ListEntriesRequest{
-		Directory: entry.Path().String(),
-	})
-	if err != nil {
-		return nil, err
-	}
-
 	iter := &dirIterator{
-		ctx:     ctx,
-		dir:     entry,
-		entries: read(ctx, f, entry, c),
-		filer:   f,
-		name:    entry.Name(),
-		let:     let,
+		ctx:   ctx,
+		dir:   entry,
+		filer: let.cluster.Filer(),
+		name:  entry.Name(),
+		let:   let,
+		opts:  filer.ListOptions{Limit: dirBatchSize},
 	}
 	iter.hasNext.Swap(true)
 	return iter, nil
@@ -73,23 +66,53 @@ func (i *dirIterator) HasNext() bool {
 //
 // The error io.EOF is returned if there are no remaining list left to iterate.
 func (i *dirIterator) Next() (*fs.Entry, error) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
 	if !i.HasNext() {
 		return nil, io.EOF
 	}
 
-	de := <-i.entries
-	if de.err != nil {
-		i.hasNext.Swap(false)
-		return nil, de.err
+	if len(i.batch) == 0 {
+		if err := i.fetch(); err != nil {
+			i.hasNext.Swap(false)
+			return nil, err
+		}
 	}
 
-	e, err := FSEntry(i.let, de.entry)
+	e, err := FSEntry(i.let, i.batch[0])
 	if err != nil {
 		return nil, err
 	}
+	i.batch = i.batch[1:]
 	return e, nil
 }
 
+// fetch retrieves the next batch of entries for the directory, starting after the last entry of the previous batch.
+//
+// The error io.EOF is returned if there are no remaining entries.
+func (i *dirIterator) fetch() error {
+	if i.done {
+		return io.EOF
+	}
+
+	entries, err := i.filer.List(i.ctx, i.dir.Path().String(), i.opts)
+	if err != nil {
+		return err
+	}
+
+	if len(entries) < int(i.opts.Limit) {
+		i.done = true
+	}
+
+	if len(entries) == 0 {
+		return io.EOF
+	}
+	i.batch = entries
+	i.opts.StartFrom = entries[len(entries)-1].Name()
+	return nil
+}
+
 // NextN returns a slice containing the next n directory list. Dot list "." are skipped.
 //
 // The error io.EOF is returned if there are no remaining list left to iterate.
@@ -118,35 +141,3 @@ func (i *dirIterator) NextN(n int) ([]*fs.Entry, error) {
 	}
 	return entries, nil
 }
-
-func read(ctx context.Context, filer *filer.Filer, dir *filer.Entry, c filer_pb.SeaweedFiler_ListEntriesClient) <-chan dirEntry {
-	entries := make(chan dirEntry)
-	go func() {
-		defer close(entries)
-
-		for {
-			dirEntry := dirEntry{}
-			resp, err := c.Recv()
-			if err != nil {
-				dirEntry.err = err
-				entries <- dirEntry
-				break
-			}
-
-			n, err := filer.NewEntry(dir.Name(), resp.GetEntry())
-			if err != nil {
-				dirEntry.err = err
-				entries <- dirEntry
-				break
-			}
-			dirEntry.entry = n
-
-			select {
-			case entries <- dirEntry:
-			case <-ctx.Done():
-				return
-			}
-		}
-	}()
-	return entries
-}
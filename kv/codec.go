@@ -0,0 +1,131 @@
+package kv
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+
+	json "github.com/json-iterator/go"
+)
+
+// Codec defines the behavior for encoding and decoding values of type T stored using a Store.
+type Codec[T any] interface {
+	// Decode decodes a value of type T from the provided bytes.
+	Decode(b []byte) (T, error)
+
+	// Encode encodes the provided value of type T.
+	Encode(v T) ([]byte, error)
+}
+
+// JSON returns a Codec that encodes values of type T as JSON.
+//
+// Values are encoded the same way as by the encoding/json package, including sorting map keys, so that equal values
+// have equal encodings, which is required for the comparison performed by Typed.CompareAndSwap.
+func JSON[T any]() Codec[T] {
+	return jsonCodec[T]{}
+}
+
+type jsonCodec[T any] struct{}
+
+func (jsonCodec[T]) Decode(b []byte) (T, error) {
+	var v T
+	if err := json.ConfigCompatibleWithStandardLibrary.Unmarshal(b, &v); err != nil {
+		return v, err
+	}
+	return v, nil
+}
+
+func (jsonCodec[T]) Encode(v T) ([]byte, error) {
+	return json.ConfigCompatibleWithStandardLibrary.Marshal(v)
+}
+
+// Protobuf returns a Codec that encodes protocol buffer messages of type T using the binary wire format.
+//
+// Messages are encoded deterministically, so that equal messages have equal encodings, which is required for the
+// comparison performed by Typed.CompareAndSwap.
+func Protobuf[T proto.Message]() Codec[T] {
+	return protobufCodec[T]{}
+}
+
+type protobufCodec[T proto.Message] struct{}
+
+func (protobufCodec[T]) Decode(b []byte) (T, error) {
+	var zero T
+	v, ok := zero.ProtoReflect().New().Interface().(T)
+	if !ok {
+		return zero, fmt.Errorf("invalid message type: %T", zero)
+	}
+
+	if err := proto.Unmarshal(b, v); err != nil {
+		return zero, err
+	}
+	return v, nil
+}
+
+func (protobufCodec[T]) Encode(v T) ([]byte, error) {
+	return proto.MarshalOptions{Deterministic: true}.Marshal(v)
+}
+
+// Typed provides access to values of type T stored using a Store, which are encoded and decoded using a Codec.
+type Typed[T any] struct {
+	codec Codec[T]
+	store *Store
+}
+
+// NewTyped creates a new Typed for values of type T stored using the provided Store and Codec.
+func NewTyped[T any](s *Store, c Codec[T]) *Typed[T] {
+	return &Typed[T]{codec: c, store: s}
+}
+
+// CompareAndSwap stores the value new for the key if the current value is equal to old, and returns whether the value
+// was stored. Values are compared using their encoding. See Store.CompareAndSwap for details.
+func (t *Typed[T]) CompareAndSwap(ctx context.Context, key string, old T, new T) (bool, error) {
+	o, err := t.codec.Encode(old)
+	if err != nil {
+		return false, fmt.Errorf("kv: %w", err)
+	}
+
+	n, err := t.codec.Encode(new)
+	if err != nil {
+		return false, fmt.Errorf("kv: %w", err)
+	}
+	return t.store.CompareAndSwap(ctx, key, o, n)
+}
+
+// Create stores the value for the key if the key does not have a value, and returns whether the value was stored.
+func (t *Typed[T]) Create(ctx context.Context, key string, v T) (bool, error) {
+	b, err := t.codec.Encode(v)
+	if err != nil {
+		return false, fmt.Errorf("kv: %w", err)
+	}
+	return t.store.CompareAndSwap(ctx, key, nil, b)
+}
+
+// Delete removes the value for the key. See Store.Delete for details.
+func (t *Typed[T]) Delete(ctx context.Context, key string) error {
+	return t.store.Delete(ctx, key)
+}
+
+// Get returns the decoded value for the key. The error ErrNotFound is returned if the key does not have a value.
+func (t *Typed[T]) Get(ctx context.Context, key string) (T, error) {
+	var v T
+	b, err := t.store.Get(ctx, key)
+	if err != nil {
+		return v, err
+	}
+
+	if v, err = t.codec.Decode(b); err != nil {
+		return v, fmt.Errorf("kv: %w", err)
+	}
+	return v, nil
+}
+
+// Put encodes and stores the value for the key. See Store.Put for details.
+func (t *Typed[T]) Put(ctx context.Context, key string, v T) error {
+	b, err := t.codec.Encode(v)
+	if err != nil {
+		return fmt.Errorf("kv: %w", err)
+	}
+	return t.store.Put(ctx, key, b)
+}
@@ -0,0 +1,92 @@
+package kv
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/transientvariable/lettuce/pb/filer_pb"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+type testCursor struct {
+	Offset int64             `json:"offset"`
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+func TestJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		v    testCursor
+		want string
+	}{
+		{name: "zero", want: `{"offset":0}`},
+		{name: "offset", v: testCursor{Offset: 42}, want: `{"offset":42}`},
+		{
+			name: "sorted map keys",
+			v:    testCursor{Offset: 1, Labels: map[string]string{"zone": "a", "app": "b", "mode": "c"}},
+			want: `{"offset":1,"labels":{"app":"b","mode":"c","zone":"a"}}`,
+		},
+	}
+
+	c := JSON[testCursor]()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := c.Encode(tt.v)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, string(b))
+
+			v, err := c.Decode(b)
+			require.NoError(t, err)
+			assert.Equal(t, tt.v, v)
+		})
+	}
+}
+
+func TestJSONDecodeInvalid(t *testing.T) {
+	_, err := JSON[testCursor]().Decode([]byte(`{"offset":`))
+	assert.Error(t, err)
+}
+
+func TestProtobuf(t *testing.T) {
+	c := Protobuf[*filer_pb.Entry]()
+
+	v := &filer_pb.Entry{Name: "cursor", Extended: map[string][]byte{"a": []byte("1"), "b": []byte("2")}}
+	b, err := c.Encode(v)
+	require.NoError(t, err)
+
+	got, err := c.Decode(b)
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(v, got))
+}
+
+func TestProtobufDeterministic(t *testing.T) {
+	c := Protobuf[*filer_pb.Entry]()
+
+	extended := make(map[string][]byte)
+	for i := range 32 {
+		extended[fmt.Sprintf("key-%d", i)] = []byte{byte(i)}
+	}
+
+	want, err := c.Encode(&filer_pb.Entry{Extended: extended})
+	require.NoError(t, err)
+
+	for range 10 {
+		// Maps with the same entries inserted in a different order must have the same encoding.
+		m := make(map[string][]byte)
+		for i := 31; i >= 0; i-- {
+			m[fmt.Sprintf("key-%d", i)] = []byte{byte(i)}
+		}
+
+		b, err := c.Encode(&filer_pb.Entry{Extended: m})
+		require.NoError(t, err)
+		assert.Equal(t, want, b)
+	}
+}
+
+func TestProtobufDecodeInvalid(t *testing.T) {
+	_, err := Protobuf[*filer_pb.Entry]().Decode([]byte{0xff})
+	assert.Error(t, err)
+}
@@ -0,0 +1,15 @@
+package kv
+
+// Enumeration of errors that may be returned by key-value store operations.
+const (
+	ErrEmptyValue = kvError("value is empty")
+	ErrNotFound   = kvError("key not found")
+)
+
+// kvError defines the type for errors that may be returned by key-value store operations.
+type kvError string
+
+// Error returns the cause of a key-value store operation error.
+func (e kvError) Error() string {
+	return string(e)
+}
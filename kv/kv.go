@@ -0,0 +1,209 @@
+// Package kv provides a key-value store backed by the key-value store of a SeaweedFS filer, which is suitable for
+// small coordination state such as cursors, checkpoints and leases.
+package kv
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/transientvariable/lettuce/cluster/filer"
+	"github.com/transientvariable/log-go"
+)
+
+const (
+	// LockTTL defines the default time-to-live for the distributed locks used by Store.CompareAndSwap.
+	LockTTL = 10 * time.Second
+)
+
+// Store is a key-value store backed by a SeaweedFS filer.
+//
+// Keys are namespaced using the prefix for the Store (see WithPrefix and Store.Prefix), so that stores with different
+// prefixes can share a filer without conflicts.
+type Store struct {
+	filer   *filer.Filer
+	lockTTL time.Duration
+	owner   string
+	prefix  string
+}
+
+// New creates a new Store backed by the provided filer.Filer using the provided options.
+func New(f *filer.Filer, options ...func(*Store)) (*Store, error) {
+	if f == nil {
+		return nil, errors.New("kv: filer is required")
+	}
+
+	s := &Store{filer: f, lockTTL: LockTTL}
+	for _, opt := range options {
+		opt(s)
+	}
+
+	if s.owner == "" {
+		host, err := os.Hostname()
+		if err != nil {
+			host = "kv"
+		}
+		s.owner = fmt.Sprintf("%s:%d", host, os.Getpid())
+	}
+	return s, nil
+}
+
+// CompareAndSwap stores the value new for the key if the current value is equal to old, and returns whether the value
+// was stored. A nil value for old means that the key must not have a value, and a nil value for new removes the value
+// for the key.
+//
+// The comparison and update are performed while holding a distributed lock for the key, so concurrent calls to
+// CompareAndSwap for the same key are serialized across clients. Calls to Store.Put and Store.Delete do not acquire the
+// lock, so keys updated using CompareAndSwap should not be updated using Put or Delete.
+//
+// The filer does not fence updates using the lock, so the serialization relies on the lock outliving the call. The
+// update is not made if the loss of the lock has been reported (see filer.Lock.Lost), but an update that is delayed
+// until after the lock has expired (e.g. by a slow filer or a paused process) may still overwrite a value stored by
+// another client that acquired the lock in the meantime. The lock time-to-live (see WithLockTTL) should therefore be
+// much longer than the time taken by the call.
+func (s *Store) CompareAndSwap(ctx context.Context, key string, old []byte, new []byte) (bool, error) {
+	log.Trace("[kv] compareAndSwap", log.String("key", s.key(key)))
+
+	if key == "" {
+		return false, fmt.Errorf("kv: key is required")
+	}
+
+	lock, err := s.filer.Lock(ctx, "kv:"+s.key(key), s.lockTTL, s.owner)
+	if err != nil {
+		return false, fmt.Errorf("kv: %w", err)
+	}
+	defer func() {
+		if err := lock.Release(context.WithoutCancel(ctx)); err != nil {
+			log.Error("[kv] could not release lock", log.String("key", s.key(key)), log.Err(err))
+		}
+	}()
+
+	v, err := s.filer.KvGet(ctx, []byte(s.key(key)))
+	if err != nil {
+		return false, fmt.Errorf("kv: %w", err)
+	}
+
+	if !matches(old, v) {
+		return false, nil
+	}
+
+	// Ownership of the lock may have been lost while the value was retrieved, in which case another client may change
+	// the value. A lock that expires after this point is not detected, since the filer does not fence the update.
+	select {
+	case err := <-lock.Lost():
+		return false, fmt.Errorf("kv: %w", err)
+	default:
+	}
+
+	if new == nil {
+		err = s.filer.KvDelete(ctx, []byte(s.key(key)))
+	} else {
+		err = s.put(ctx, key, new)
+	}
+
+	if err != nil {
+		return false, fmt.Errorf("kv: %w", err)
+	}
+	return true, nil
+}
+
+// Delete removes the value for the key. Removing a key that does not have a value is not an error.
+func (s *Store) Delete(ctx context.Context, key string) error {
+	log.Trace("[kv] delete", log.String("key", s.key(key)))
+
+	if key == "" {
+		return fmt.Errorf("kv: key is required")
+	}
+
+	if err := s.filer.KvDelete(ctx, []byte(s.key(key))); err != nil {
+		return fmt.Errorf("kv: %w", err)
+	}
+	return nil
+}
+
+// Get returns the value for the key. The error ErrNotFound is returned if the key does not have a value.
+func (s *Store) Get(ctx context.Context, key string) ([]byte, error) {
+	log.Trace("[kv] get", log.String("key", s.key(key)))
+
+	if key == "" {
+		return nil, fmt.Errorf("kv: key is required")
+	}
+
+	v, err := s.filer.KvGet(ctx, []byte(s.key(key)))
+	if err != nil {
+		return nil, fmt.Errorf("kv: %w", err)
+	}
+
+	if v == nil {
+		return nil, fmt.Errorf("kv: %s: %w", key, ErrNotFound)
+	}
+	return v, nil
+}
+
+// Prefix returns a Store that shares the configuration of the Store, with keys namespaced by the provided prefix
+// appended to the prefix of the Store.
+func (s *Store) Prefix(prefix string) *Store {
+	return &Store{
+		filer:   s.filer,
+		lockTTL: s.lockTTL,
+		owner:   s.owner,
+		prefix:  s.prefix + prefix,
+	}
+}
+
+// Put stores the value for the key. The value must not be empty, since the filer does not distinguish between empty
+// values and keys without a value.
+func (s *Store) Put(ctx context.Context, key string, value []byte) error {
+	log.Trace("[kv] put", log.String("key", s.key(key)), log.Int("size", len(value)))
+
+	if key == "" {
+		return fmt.Errorf("kv: key is required")
+	}
+
+	if err := s.put(ctx, key, value); err != nil {
+		return fmt.Errorf("kv: %w", err)
+	}
+	return nil
+}
+
+// matches returns whether the current value v for a key is equal to the expected value old, where a nil value means
+// that the key does not have a value.
+func matches(old []byte, v []byte) bool {
+	return (old == nil) == (v == nil) && bytes.Equal(old, v)
+}
+
+func (s *Store) key(key string) string {
+	return s.prefix + key
+}
+
+func (s *Store) put(ctx context.Context, key string, value []byte) error {
+	if len(value) == 0 {
+		return ErrEmptyValue
+	}
+	return s.filer.KvPut(ctx, []byte(s.key(key)), value)
+}
+
+// WithLockTTL sets the time-to-live for the distributed locks used by Store.CompareAndSwap.
+func WithLockTTL(ttl time.Duration) func(*Store) {
+	return func(s *Store) {
+		s.lockTTL = ttl
+	}
+}
+
+// WithOwner sets the owner reported for the distributed locks used by Store.CompareAndSwap. The default owner is
+// derived from the host name and process ID.
+func WithOwner(owner string) func(*Store) {
+	return func(s *Store) {
+		s.owner = owner
+	}
+}
+
+// WithPrefix sets the prefix used for namespacing the keys for the Store (e.g. "myapp/").
+func WithPrefix(prefix string) func(*Store) {
+	return func(s *Store) {
+		s.prefix = prefix
+	}
+}
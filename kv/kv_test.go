@@ -0,0 +1,72 @@
+package kv
+
+import (
+	"context"
+	"testing"
+
+	"github.com/transientvariable/lettuce/cluster/filer"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatches(t *testing.T) {
+	tests := []struct {
+		name string
+		old  []byte
+		v    []byte
+		want bool
+	}{
+		{name: "both missing", want: true},
+		{name: "equal", old: []byte("a"), v: []byte("a"), want: true},
+		{name: "different", old: []byte("a"), v: []byte("b")},
+		{name: "expected missing", v: []byte("a")},
+		{name: "current missing", old: []byte("a")},
+		{name: "expected empty", old: []byte{}},
+		{name: "expected empty current set", old: []byte{}, v: []byte("a")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, matches(tt.old, tt.v))
+		})
+	}
+}
+
+func TestStorePrefix(t *testing.T) {
+	s, err := New(&filer.Filer{}, WithPrefix("app/"), WithOwner("test"))
+	require.NoError(t, err)
+
+	p := s.Prefix("cursors/")
+	assert.Equal(t, "app/cursors/k", p.key("k"))
+	assert.Equal(t, "app/k", s.key("k"))
+	assert.Equal(t, s.owner, p.owner)
+	assert.Equal(t, s.lockTTL, p.lockTTL)
+}
+
+func TestStoreKeyRequired(t *testing.T) {
+	s, err := New(&filer.Filer{})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	tests := []struct {
+		name string
+		fn   func() error
+	}{
+		{name: "compareAndSwap", fn: func() error { _, err := s.CompareAndSwap(ctx, "", nil, []byte("a")); return err }},
+		{name: "delete", fn: func() error { return s.Delete(ctx, "") }},
+		{name: "get", fn: func() error { _, err := s.Get(ctx, ""); return err }},
+		{name: "put", fn: func() error { return s.Put(ctx, "", []byte("a")) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorContains(t, tt.fn(), "key is required")
+		})
+	}
+}
+
+func TestNewFilerRequired(t *testing.T) {
+	_, err := New(nil)
+	assert.Error(t, err)
+}
@@ -2,7 +2,9 @@ package lettuce
 
 import (
 	"context"
+	"time"
 
+	"github.com/transientvariable/lettuce/chunk"
 	"github.com/transientvariable/lettuce/cluster"
 	"github.com/transientvariable/lettuce/cluster/filer"
 
@@ -23,6 +25,30 @@ func WithEntry(e *filer.Entry) func(*File) {
 	}
 }
 
+// WithFileCompression sets the chunk.Compression used for content written to the File, overriding the compression
+// policy of the Lettuce instance. Use chunk.CompressionNone to disable compression for the File.
+func WithFileCompression(c chunk.Compression) func(*File) {
+	return func(f *File) {
+		f.compress = &c
+	}
+}
+
+// WithFilePlacement sets the filer.Placement options (e.g. filer.WithCollection, filer.WithDiskType) for content written
+// to the File, overriding the options set for the Lettuce (see WithPlacement).
+func WithFilePlacement(options ...func(*filer.Placement)) func(*File) {
+	return func(f *File) {
+		f.placement = options
+	}
+}
+
+// WithFileTTL sets the time-to-live for a file created when opening the File, overriding the time-to-live set for the
+// Lettuce (see WithTTL). It has no effect on files that already exist.
+func WithFileTTL(ttl time.Duration) func(*File) {
+	return func(f *File) {
+		f.ttl = &ttl
+	}
+}
+
 // WithHTTPClient sets the http.Client used for read/write operations for a File.
 func WithHTTPClient(c *gohttp.Client) func(*File) {
 	return func(f *File) {
@@ -37,6 +63,30 @@ func WithCluster(c *cluster.Cluster) func(*Lettuce) {
 	}
 }
 
+// WithCompression sets the chunk.Compression used for content written to files with one of the provided MIME types,
+// which are determined using the file extension. If no MIME types are provided, the content for all files is
+// compressed.
+//
+// Compression is disabled by default. It is only worth enabling for content that compresses well (e.g. text/*), since
+// it is applied to each chunk before upload. Content that has already been compressed is read transparently
+// regardless of this setting.
+func WithCompression(c chunk.Compression, mimeTypes ...string) func(*Lettuce) {
+	return func(s *Lettuce) {
+		s.compress = compressionPolicy{compression: c, mimeTypes: mimeTypes}
+	}
+}
+
+// WithEncryption sets whether file content is encrypted before it is uploaded to volume servers. Each chunk is
+// encrypted using AES-GCM with a random key stored in the chunk metadata held by the filer, which is the same scheme
+// used by SeaweedFS, so encrypted files remain readable by other SeaweedFS clients.
+//
+// Content for files stored inline (see WithInlineSize) is held by the filer and is not encrypted.
+func WithEncryption(enable bool) func(*Lettuce) {
+	return func(s *Lettuce) {
+		s.encrypt = enable
+	}
+}
+
 // WithGID sets the default group ID to use when writing data.
 func WithGID(gid uint32) func(*Lettuce) {
 	return func(s *Lettuce) {
@@ -44,6 +94,35 @@ func WithGID(gid uint32) func(*Lettuce) {
 	}
 }
 
+// WithInlineSize sets the maximum size in bytes for files to be stored inline within the filer entry rather than on
+// volume servers. Storing small files inline avoids a round trip to a volume server for reads and writes.
+//
+// Only files written in full while open are stored inline. A size of 0, the default, disables inline storage.
+func WithInlineSize(size uint) func(*Lettuce) {
+	return func(s *Lettuce) {
+		s.inlineSize = int(size)
+	}
+}
+
+// WithPlacement sets the filer.Placement options (e.g. filer.WithCollection, filer.WithReplication) for content written
+// to files by the Lettuce. A file can override the options when it is opened (see WithFilePlacement).
+func WithPlacement(options ...func(*filer.Placement)) func(*Lettuce) {
+	return func(s *Lettuce) {
+		s.placement = options
+	}
+}
+
+// WithTTL sets the time-to-live for files created by the Lettuce, after which the file and its content are removed by
+// the cluster. Content for the files is stored on volumes created with the same time-to-live, so that expired content
+// is reclaimed without deleting individual chunks.
+//
+// A time-to-live less than or equal to zero means that files do not expire, which is the default.
+func WithTTL(ttl time.Duration) func(*Lettuce) {
+	return func(s *Lettuce) {
+		s.ttl = ttl
+	}
+}
+
 // WithUID sets the default user ID to use when writing data.
 func WithUID(uid uint32) func(*Lettuce) {
 	return func(s *Lettuce) {
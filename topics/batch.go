@@ -0,0 +1,186 @@
+package topics
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/transientvariable/lettuce/pb/message_fbs"
+
+	flatbuffers "github.com/google/flatbuffers/go"
+)
+
+// Batch is a batch of messages published to a topic partition by a single producer, which is encoded as a
+// message_fbs.MessageBatch.
+type Batch struct {
+	Flags         int32
+	Messages      []Message
+	ProducerEpoch int32
+	ProducerID    int32
+	SegmentID     int32
+}
+
+// Message is a message published to a topic.
+//
+// Properties and Seq are only carried by a Batch, and PartitionKeyHash is only carried by an entry in a segment.
+type Message struct {
+	Key              []byte
+	PartitionKeyHash int32
+	Properties       map[string][]byte
+	Seq              int64
+	Timestamp        time.Time
+	Value            []byte
+}
+
+// Decode decodes a Batch from the provided message_fbs.MessageBatch.
+//
+// The bytes for keys, values and properties of the returned messages reference the provided buffer.
+func Decode(buf []byte) (b *Batch, err error) {
+	if len(buf) < flatbuffers.SizeUOffsetT {
+		return nil, ErrInvalidBatch
+	}
+
+	// The generated accessors do not verify the buffer, so decoding a malformed batch may index past its end.
+	defer func() {
+		if r := recover(); r != nil {
+			b, err = nil, fmt.Errorf("%w: %v", ErrInvalidBatch, r)
+		}
+	}()
+
+	mb := message_fbs.GetRootAsMessageBatch(buf, 0)
+	b = &Batch{
+		Flags:         mb.Flags(),
+		Messages:      make([]Message, mb.MessagesLength()),
+		ProducerEpoch: mb.ProducerEpoch(),
+		ProducerID:    mb.ProducerId(),
+		SegmentID:     mb.SegmentId(),
+	}
+
+	var m message_fbs.Message
+	for i := range b.Messages {
+		if !mb.Messages(&m, i) {
+			return nil, ErrInvalidBatch
+		}
+
+		b.Messages[i] = Message{
+			Key:       m.Key(),
+			Seq:       mb.SegmentSeqBase() + int64(m.SeqDelta()),
+			Timestamp: time.UnixMilli(mb.TsMsBase() + int64(m.TsMsDelta())),
+			Value:     m.Data(),
+		}
+
+		if n := m.PropertiesLength(); n > 0 {
+			props := make(map[string][]byte, n)
+			var nv message_fbs.NameValue
+			for j := range n {
+				if m.Properties(&nv, j) {
+					props[string(nv.Name())] = nv.Value()
+				}
+			}
+			b.Messages[i].Properties = props
+		}
+	}
+	return b, nil
+}
+
+// Encode encodes the Batch as a message_fbs.MessageBatch, which can be decoded using Decode.
+//
+// Sequence numbers and timestamps are encoded as deltas from the smallest sequence number and timestamp of the
+// messages in the Batch.
+func (b *Batch) Encode() ([]byte, error) {
+	if len(b.Messages) == 0 {
+		return nil, fmt.Errorf("%w: batch has no messages", ErrInvalidBatch)
+	}
+
+	seqBase, tsBase := b.Messages[0].Seq, b.Messages[0].Timestamp.UnixMilli()
+	for _, m := range b.Messages[1:] {
+		seqBase = min(seqBase, m.Seq)
+		tsBase = min(tsBase, m.Timestamp.UnixMilli())
+	}
+
+	builder := flatbuffers.NewBuilder(1024)
+	msgs := make([]flatbuffers.UOffsetT, len(b.Messages))
+	var seqMaxDelta, tsMaxDelta int32
+	for i, m := range b.Messages {
+		seqDelta, err := delta(m.Seq, seqBase)
+		if err != nil {
+			return nil, err
+		}
+
+		tsDelta, err := delta(m.Timestamp.UnixMilli(), tsBase)
+		if err != nil {
+			return nil, err
+		}
+		seqMaxDelta, tsMaxDelta = max(seqMaxDelta, seqDelta), max(tsMaxDelta, tsDelta)
+
+		props := encodeProperties(builder, m.Properties)
+		key := builder.CreateByteVector(m.Key)
+		data := builder.CreateByteVector(m.Value)
+
+		message_fbs.MessageStart(builder)
+		message_fbs.MessageAddSeqDelta(builder, seqDelta)
+		message_fbs.MessageAddTsMsDelta(builder, tsDelta)
+		if props != 0 {
+			message_fbs.MessageAddProperties(builder, props)
+		}
+		message_fbs.MessageAddKey(builder, key)
+		message_fbs.MessageAddData(builder, data)
+		msgs[i] = message_fbs.MessageEnd(builder)
+	}
+
+	message_fbs.MessageBatchStartMessagesVector(builder, len(msgs))
+	for _, m := range slices.Backward(msgs) {
+		builder.PrependUOffsetT(m)
+	}
+	vec := builder.EndVector(len(msgs))
+
+	message_fbs.MessageBatchStart(builder)
+	message_fbs.MessageBatchAddProducerId(builder, b.ProducerID)
+	message_fbs.MessageBatchAddProducerEpoch(builder, b.ProducerEpoch)
+	message_fbs.MessageBatchAddSegmentId(builder, b.SegmentID)
+	message_fbs.MessageBatchAddFlags(builder, b.Flags)
+	message_fbs.MessageBatchAddSegmentSeqBase(builder, seqBase)
+	message_fbs.MessageBatchAddSegmentSeqMaxDelta(builder, seqMaxDelta)
+	message_fbs.MessageBatchAddTsMsBase(builder, tsBase)
+	message_fbs.MessageBatchAddTsMsMaxDelta(builder, tsMaxDelta)
+	message_fbs.MessageBatchAddMessages(builder, vec)
+	message_fbs.FinishMessageBatchBuffer(builder, message_fbs.MessageBatchEnd(builder))
+	return builder.FinishedBytes(), nil
+}
+
+// encodeProperties encodes the provided properties as a vector of message_fbs.NameValue sorted by name, which is
+// required for message_fbs.Message.PropertiesByKey. Zero is returned if there are no properties.
+func encodeProperties(builder *flatbuffers.Builder, props map[string][]byte) flatbuffers.UOffsetT {
+	if len(props) == 0 {
+		return 0
+	}
+
+	names := slices.Sorted(maps.Keys(props))
+
+	nvs := make([]flatbuffers.UOffsetT, len(names))
+	for i, n := range names {
+		name := builder.CreateString(n)
+		value := builder.CreateByteVector(props[n])
+
+		message_fbs.NameValueStart(builder)
+		message_fbs.NameValueAddName(builder, name)
+		message_fbs.NameValueAddValue(builder, value)
+		nvs[i] = message_fbs.NameValueEnd(builder)
+	}
+
+	message_fbs.MessageStartPropertiesVector(builder, len(nvs))
+	for _, nv := range slices.Backward(nvs) {
+		builder.PrependUOffsetT(nv)
+	}
+	return builder.EndVector(len(nvs))
+}
+
+func delta(v int64, base int64) (int32, error) {
+	d := v - base
+	if d > math.MaxInt32 {
+		return 0, fmt.Errorf("%w: delta out of range: %d", ErrInvalidBatch, d)
+	}
+	return int32(d), nil
+}
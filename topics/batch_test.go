@@ -0,0 +1,147 @@
+package topics
+
+import (
+	"bytes"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/transientvariable/lettuce/pb/message_fbs"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatch(t *testing.T) {
+	ts := time.UnixMilli(1700000000123)
+
+	tests := []struct {
+		name  string
+		batch Batch
+	}{
+		{
+			name:  "single message",
+			batch: Batch{Messages: []Message{{Key: []byte("k"), Seq: 7, Timestamp: ts, Value: []byte("v")}}},
+		},
+		{
+			name: "producer",
+			batch: Batch{
+				Flags:         1,
+				Messages:      []Message{{Seq: 1, Timestamp: ts, Value: []byte("v")}},
+				ProducerEpoch: 2,
+				ProducerID:    3,
+				SegmentID:     4,
+			},
+		},
+		{
+			name: "unordered messages",
+			batch: Batch{Messages: []Message{
+				{Key: []byte("b"), Seq: 12, Timestamp: ts.Add(time.Second), Value: []byte("2")},
+				{Key: []byte("a"), Seq: 10, Timestamp: ts, Value: []byte("1")},
+				{Key: []byte("c"), Seq: 11, Timestamp: ts.Add(time.Minute), Value: []byte("3")},
+			}},
+		},
+		{
+			name: "properties",
+			batch: Batch{Messages: []Message{{
+				Properties: map[string][]byte{"trace": []byte("abc"), "content-type": []byte("json")},
+				Seq:        1,
+				Timestamp:  ts,
+				Value:      []byte("v"),
+			}}},
+		},
+		{
+			name:  "empty key and value",
+			batch: Batch{Messages: []Message{{Seq: 1, Timestamp: ts}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf, err := tt.batch.Encode()
+			require.NoError(t, err)
+
+			b, err := Decode(buf)
+			require.NoError(t, err)
+			assert.Equal(t, tt.batch.Flags, b.Flags)
+			assert.Equal(t, tt.batch.ProducerEpoch, b.ProducerEpoch)
+			assert.Equal(t, tt.batch.ProducerID, b.ProducerID)
+			assert.Equal(t, tt.batch.SegmentID, b.SegmentID)
+			require.Len(t, b.Messages, len(tt.batch.Messages))
+
+			for i, want := range tt.batch.Messages {
+				m := b.Messages[i]
+				assert.True(t, bytes.Equal(want.Key, m.Key))
+				assert.True(t, bytes.Equal(want.Value, m.Value))
+				assert.Equal(t, want.Seq, m.Seq)
+				assert.True(t, want.Timestamp.Equal(m.Timestamp))
+				assert.Equal(t, want.Properties, m.Properties)
+			}
+		})
+	}
+}
+
+func TestBatchPropertiesByKey(t *testing.T) {
+	props := map[string][]byte{"c": []byte("3"), "a": []byte("1"), "b": []byte("2")}
+	buf, err := (&Batch{Messages: []Message{{Properties: props, Timestamp: time.UnixMilli(1)}}}).Encode()
+	require.NoError(t, err)
+
+	var m message_fbs.Message
+	require.True(t, message_fbs.GetRootAsMessageBatch(buf, 0).Messages(&m, 0))
+
+	for k, v := range props {
+		var nv message_fbs.NameValue
+		require.True(t, m.PropertiesByKey(&nv, k), k)
+		assert.Equal(t, v, nv.Value())
+	}
+}
+
+func TestBatchEncodeInvalid(t *testing.T) {
+	ts := time.UnixMilli(1700000000000)
+
+	tests := []struct {
+		name  string
+		batch Batch
+	}{
+		{name: "no messages"},
+		{
+			name: "sequence delta out of range",
+			batch: Batch{Messages: []Message{
+				{Seq: 0, Timestamp: ts},
+				{Seq: math.MaxInt32 + 1, Timestamp: ts},
+			}},
+		},
+		{
+			name: "timestamp delta out of range",
+			batch: Batch{Messages: []Message{
+				{Timestamp: ts},
+				{Timestamp: ts.Add((math.MaxInt32 + 1) * time.Millisecond)},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.batch.Encode()
+			assert.ErrorIs(t, err, ErrInvalidBatch)
+		})
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		buf  []byte
+	}{
+		{name: "empty"},
+		{name: "too short", buf: []byte{1, 2}},
+		{name: "malformed", buf: []byte{0xff, 0xff, 0xff, 0x7f, 0, 0, 0, 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Decode(tt.buf)
+			assert.ErrorIs(t, err, ErrInvalidBatch)
+		})
+	}
+}
@@ -0,0 +1,15 @@
+package topics
+
+// Enumeration of errors that may be returned by topic operations.
+const (
+	ErrInvalidBatch = topicError("invalid message batch")
+	ErrInvalidEntry = topicError("invalid segment entry")
+)
+
+// topicError defines the type for errors that may be returned by topic operations.
+type topicError string
+
+// Error returns the cause of a topic operation error.
+func (e topicError) Error() string {
+	return string(e)
+}
@@ -0,0 +1,120 @@
+package topics
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/transientvariable/lettuce/pb/filer_pb"
+
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// MaxEntrySize defines the maximum size in bytes of an encoded entry in a segment.
+	MaxEntrySize = 64 << 20
+
+	// SegmentTimeFormat defines the layout of the time in the name of a segment, which is the time the segment was
+	// created. Other files in the directory of a topic, such as topic.conf and the *.parquet and *.offset files written
+	// by the broker, are not segments.
+	SegmentTimeFormat = "2006-01-02-15-04-05"
+
+	// entrySizeLen defines the length in bytes of the size that prefixes each entry in a segment.
+	entrySizeLen = 4
+)
+
+// SegmentName returns the name of a segment created at the provided time.
+func SegmentName(t time.Time) string {
+	return t.UTC().Format(SegmentTimeFormat)
+}
+
+// IsSegment returns whether the provided file name is the name of a segment (see SegmentName).
+func IsSegment(name string) bool {
+	_, err := time.Parse(SegmentTimeFormat, name)
+	return err == nil
+}
+
+// Reader reads the messages stored in a segment.
+//
+// Segments are written by the log buffer of the broker, and consist of a sequence of filer_pb.LogEntry values each
+// prefixed with its size as a big-endian uint32.
+type Reader struct {
+	r    io.Reader
+	size [entrySizeLen]byte
+}
+
+// NewReader creates a new Reader for the segment read from the provided io.Reader.
+func NewReader(r io.Reader) *Reader {
+	return &Reader{r: r}
+}
+
+// Close closes the io.Reader for the Reader if it implements io.Closer.
+func (r *Reader) Close() error {
+	if c, ok := r.r.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// Next returns the next Message in the segment. The error io.EOF is returned when there are no more messages.
+func (r *Reader) Next() (Message, error) {
+	if _, err := io.ReadFull(r.r, r.size[:]); err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			return Message{}, fmt.Errorf("%w: truncated size", ErrInvalidEntry)
+		}
+		return Message{}, err
+	}
+
+	size := binary.BigEndian.Uint32(r.size[:])
+	if size > MaxEntrySize {
+		return Message{}, fmt.Errorf("%w: size exceeds maximum: %d", ErrInvalidEntry, size)
+	}
+
+	buf := make([]byte, size)
+	if _, err := io.ReadFull(r.r, buf); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return Message{}, fmt.Errorf("%w: truncated entry", ErrInvalidEntry)
+		}
+		return Message{}, err
+	}
+
+	e := &filer_pb.LogEntry{}
+	if err := proto.Unmarshal(buf, e); err != nil {
+		return Message{}, fmt.Errorf("%w: %w", ErrInvalidEntry, err)
+	}
+
+	return Message{
+		Key:              e.GetKey(),
+		PartitionKeyHash: e.GetPartitionKeyHash(),
+		Timestamp:        time.Unix(0, e.GetTsNs()),
+		Value:            e.GetData(),
+	}, nil
+}
+
+// appendEntry appends the provided Message to b as an entry in a segment and returns the extended buffer. The current
+// time is used for a Message without a timestamp.
+func appendEntry(b []byte, m Message) ([]byte, error) {
+	ts := m.Timestamp
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+
+	e, err := proto.Marshal(&filer_pb.LogEntry{
+		Data:             m.Value,
+		Key:              m.Key,
+		PartitionKeyHash: m.PartitionKeyHash,
+		TsNs:             ts.UnixNano(),
+	})
+	if err != nil {
+		return b, err
+	}
+
+	if len(e) > MaxEntrySize {
+		return b, fmt.Errorf("%w: size exceeds maximum: %d", ErrInvalidEntry, len(e))
+	}
+
+	b = binary.BigEndian.AppendUint32(b, uint32(len(e)))
+	return append(b, e...), nil
+}
@@ -0,0 +1,106 @@
+package topics
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// segmentMessages are the messages stored in testdata/segment.bin, which is encoded the same way as the segments
+// written by the log buffer of the broker.
+var segmentMessages = []Message{
+	{
+		Key:              []byte("k1"),
+		PartitionKeyHash: 17,
+		Timestamp:        time.Unix(0, 1700000000000000001),
+		Value:            []byte("hello"),
+	},
+	{
+		PartitionKeyHash: -5,
+		Timestamp:        time.Unix(0, 1700000000000000002),
+		Value:            []byte(`{"a":1}`),
+	},
+	{
+		Key:       []byte("k3"),
+		Timestamp: time.Unix(0, 1700000000500000000),
+	},
+}
+
+func TestReader(t *testing.T) {
+	b, err := os.ReadFile(filepath.Join("testdata", "segment.bin"))
+	require.NoError(t, err)
+
+	r := NewReader(bytes.NewReader(b))
+	for _, want := range segmentMessages {
+		m, err := r.Next()
+		require.NoError(t, err)
+		assert.Equal(t, want.Key, m.Key)
+		assert.Equal(t, want.PartitionKeyHash, m.PartitionKeyHash)
+		assert.True(t, want.Timestamp.Equal(m.Timestamp))
+		assert.Equal(t, want.Value, m.Value)
+	}
+
+	_, err = r.Next()
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestReaderInvalid(t *testing.T) {
+	b, err := os.ReadFile(filepath.Join("testdata", "segment.bin"))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		b    []byte
+	}{
+		{name: "truncated size", b: b[:2]},
+		{name: "truncated entry", b: b[:10]},
+		{name: "size exceeds maximum", b: []byte{0xff, 0xff, 0xff, 0xff}},
+		{name: "invalid entry", b: []byte{0, 0, 0, 2, 0xff, 0xff}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewReader(bytes.NewReader(tt.b)).Next()
+			assert.ErrorIs(t, err, ErrInvalidEntry)
+		})
+	}
+}
+
+func TestAppendEntry(t *testing.T) {
+	want, err := os.ReadFile(filepath.Join("testdata", "segment.bin"))
+	require.NoError(t, err)
+
+	var b []byte
+	for _, m := range segmentMessages {
+		b, err = appendEntry(b, m)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, want, b)
+}
+
+func TestIsSegment(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{name: SegmentName(time.Date(2024, 5, 30, 16, 8, 57, 0, time.UTC)), want: true},
+		{name: "2024-05-30-16-08-57", want: true},
+		{name: "topic.conf"},
+		{name: "2024-05-30-16-08-57.parquet"},
+		{name: "0000-0630.offset"},
+		{name: "v2024-05-30-16-08-57"},
+		{name: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsSegment(tt.name))
+		})
+	}
+}
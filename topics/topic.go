@@ -0,0 +1,211 @@
+// Package topics provides access to the message log segments stored by the SeaweedFS message queue, which allows
+// messages to be consumed and produced by batch jobs without a broker.
+//
+// Segments are stored under /topics/<namespace>/<topic>/ in the filer, and consist of a sequence of filer_pb.LogEntry
+// values each prefixed with its size (see Reader), and are named using the time they were created (see SegmentName).
+//
+// Batch is a standalone codec for the message_fbs.MessageBatch format used by producers to publish messages to a
+// broker, which is not the format of segments and is not used by Topic.
+package topics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/transientvariable/fs-go"
+	"github.com/transientvariable/lettuce"
+	"github.com/transientvariable/log-go"
+
+	gofs "io/fs"
+)
+
+const (
+	// Dir defines the directory relative to the filer root where topics are stored.
+	Dir = "topics"
+)
+
+// Topic provides access to the segments for a message queue topic.
+type Topic struct {
+	dir       string
+	let       *lettuce.Lettuce
+	name      string
+	namespace string
+}
+
+// New creates a new Topic for the topic with the provided namespace and name.
+//
+// The provided Lettuce must be rooted at the filer root, since topics are stored under /topics.
+func New(let *lettuce.Lettuce, namespace string, name string) (*Topic, error) {
+	if let == nil {
+		return nil, errors.New("topics: lettuce backend is required")
+	}
+
+	for _, s := range []string{namespace, name} {
+		if s == "" || strings.Contains(s, "/") || s == "." || s == ".." {
+			return nil, fmt.Errorf("topics: invalid namespace or topic name: %s/%s", namespace, name)
+		}
+	}
+
+	return &Topic{
+		dir:       path.Join(Dir, namespace, name),
+		let:       let,
+		name:      name,
+		namespace: namespace,
+	}, nil
+}
+
+// Append appends the provided messages to the segment with the provided path relative to the directory for the Topic,
+// creating the segment and its parent directories if they do not exist. The base name of the path must be the name of
+// a segment (see SegmentName).
+//
+// The messages are committed to the segment using a single append (see lettuce.Lettuce.AppendFile), so messages
+// appended concurrently by other producers are never interleaved with them.
+func (t *Topic) Append(ctx context.Context, segment string, msgs ...Message) error {
+	log.Debug("[topics] append",
+		log.String("topic", t.String()),
+		log.String("segment", segment),
+		log.Int("messages", len(msgs)))
+
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	name := path.Join(t.dir, segment)
+	if !IsSegment(path.Base(name)) {
+		return fmt.Errorf("topics: %w", &gofs.PathError{Op: "append", Path: name, Err: gofs.ErrInvalid})
+	}
+
+	var buf []byte
+	for _, m := range msgs {
+		var err error
+		if buf, err = appendEntry(buf, m); err != nil {
+			return fmt.Errorf("topics: %w", &gofs.PathError{Op: "append", Path: name, Err: err})
+		}
+	}
+
+	if err := t.let.MkdirAllContext(ctx, path.Dir(name), gofs.ModeDir|0755); err != nil {
+		return fmt.Errorf("topics: %w", err)
+	}
+
+	if err := t.let.AppendFileContext(ctx, name, buf, 0644); err != nil {
+		return fmt.Errorf("topics: %w", err)
+	}
+	return nil
+}
+
+// Dir returns the path of the directory for the Topic relative to the filer root.
+func (t *Topic) Dir() string {
+	return t.dir
+}
+
+// Name returns the name of the Topic.
+func (t *Topic) Name() string {
+	return t.name
+}
+
+// Namespace returns the namespace of the Topic.
+func (t *Topic) Namespace() string {
+	return t.namespace
+}
+
+// Open opens the segment with the provided path relative to the directory for the Topic for reading. The returned
+// Reader must be closed when it is no longer needed.
+func (t *Topic) Open(ctx context.Context, segment string) (*Reader, error) {
+	log.Debug("[topics] open", log.String("topic", t.String()), log.String("segment", segment))
+
+	f, err := t.let.OpenFileContext(ctx, path.Join(t.dir, segment), fs.O_RDONLY, 0)
+	if err != nil {
+		return nil, fmt.Errorf("topics: %w", err)
+	}
+	return NewReader(f), nil
+}
+
+// Scan calls fn for each message in the segments for the Topic with a timestamp at or after since, in the order of the
+// segments (see Topic.Segments) and of the messages within each segment. Scanning stops if fn returns an error, which
+// is returned by Scan.
+func (t *Topic) Scan(ctx context.Context, since time.Time, fn func(segment string, m Message) error) error {
+	segments, err := t.Segments(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range segments {
+		if err := t.scan(ctx, s, since, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Segments returns the paths of the segments for the Topic relative to the directory for the Topic in lexical order,
+// which is the order they were written in, since segments are named using the time they were created. Files that are
+// not segments (see IsSegment) are skipped.
+func (t *Topic) Segments(ctx context.Context) ([]string, error) {
+	var segments []string
+	err := t.let.WalkContext(ctx, t.dir, func(name string, d gofs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return gofs.SkipDir
+			}
+			return nil
+		}
+
+		if d.Type().IsRegular() && IsSegment(d.Name()) {
+			segments = append(segments, strings.TrimPrefix(name, t.dir+"/"))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("topics: %w", err)
+	}
+
+	slices.Sort(segments)
+	return segments, nil
+}
+
+// String returns a string representation of the Topic.
+func (t *Topic) String() string {
+	return t.namespace + "/" + t.name
+}
+
+func (t *Topic) scan(ctx context.Context,
+	segment string,
+	since time.Time,
+	fn func(segment string, m Message) error,
+) error {
+	r, err := t.Open(ctx, segment)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := r.Close(); err != nil {
+			log.Warn("[topics] could not close segment", log.String("segment", segment), log.Err(err))
+		}
+	}()
+
+	for {
+		m, err := r.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("topics: %w", &gofs.PathError{Op: "scan", Path: path.Join(t.dir, segment), Err: err})
+		}
+
+		if !m.Timestamp.Before(since) {
+			if err := fn(segment, m); err != nil {
+				return err
+			}
+		}
+	}
+}